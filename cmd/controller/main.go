@@ -1,12 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"time"
 
 	"go.uber.org/zap/zapcore"
-	"golang.org/x/sync/semaphore"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -23,6 +26,7 @@ import (
 	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
 	"github.com/hhiroshell/kube-booster/pkg/controller"
 	_ "github.com/hhiroshell/kube-booster/pkg/metrics" // Register custom Prometheus metrics
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 	"github.com/hhiroshell/kube-booster/pkg/warmup"
 	webhookpkg "github.com/hhiroshell/kube-booster/pkg/webhook"
 )
@@ -51,6 +55,21 @@ func main() {
 	var nodeName string
 	var maxConcurrentWarmups int
 	var maxWarmupRPS int
+	var signingKeyFile string
+	var namespaceWeights string
+	var honorPodPriority bool
+	var shutdownGracePeriod time.Duration
+	var bestEffortMissingGate bool
+	var enableWatchdog bool
+	var watchdogMaxAge time.Duration
+	var watchdogInterval time.Duration
+	var denyInvalidAnnotations bool
+	var enableConfigStatus bool
+	var recordWarmupRuns bool
+	var warmupRunRetention time.Duration
+	var enableWarmupRunCleanup bool
+	var metricsWorkloadLabels bool
+	var metricsMaxWorkloads int
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -63,6 +82,21 @@ func main() {
 	flag.StringVar(&nodeName, "node-name", "", "Node name for node-local controller mode (enables node filtering)")
 	flag.IntVar(&maxConcurrentWarmups, "max-concurrent-warmups", 10, "Maximum concurrent warmup executions per controller instance (0 = unlimited)")
 	flag.IntVar(&maxWarmupRPS, "max-warmup-rps", 100, "Maximum aggregate warmup HTTP request rate per controller instance in requests per second (0 = unlimited)")
+	flag.StringVar(&namespaceWeights, "namespace-weights", "", "Comma-separated <namespace>=<weight> pairs giving namespaces a larger share of warmup concurrency slots (unlisted namespaces have weight 1)")
+	flag.BoolVar(&honorPodPriority, "honor-pod-priority", false, "Grant warmup concurrency slots to higher-priority pods (by PriorityClass) first")
+	flag.DurationVar(&shutdownGracePeriod, "shutdown-grace-period", controller.DefaultShutdownGracePeriod, "How long in-flight warmups may keep running after SIGTERM before they are interrupted and handed off to the next controller instance")
+	flag.BoolVar(&bestEffortMissingGate, "best-effort-warmup-missing-gate", false, "Warm up pods that request warmup but were created without the readiness gate (readiness is not held back)")
+	flag.BoolVar(&enableWatchdog, "enable-watchdog", false, "Mark pods ready when no controller has set their warmup condition within --watchdog-max-age (run in the webhook Deployment)")
+	flag.DurationVar(&watchdogMaxAge, "watchdog-max-age", controller.DefaultWatchdogMaxAge, "How long after ContainersReady a pod may wait for the warmup condition before the watchdog sets it")
+	flag.BoolVar(&enableConfigStatus, "enable-warmupconfig-status", false, "Maintain the status of WarmupConfig objects from the warmup results recorded on pods (run in the webhook Deployment)")
+	flag.BoolVar(&recordWarmupRuns, "record-warmup-runs", false, "Create a WarmupRun object recording each finished warmup")
+	flag.DurationVar(&warmupRunRetention, "warmup-run-retention", 0, "How long WarmupRuns are kept after the warmup, even if the pod is deleted (0 = delete them with the pod)")
+	flag.BoolVar(&enableWarmupRunCleanup, "enable-warmup-run-cleanup", false, "Delete WarmupRuns whose retention has expired (run in the webhook Deployment)")
+	flag.BoolVar(&metricsWorkloadLabels, "metrics-workload-labels", false, "Add workload_kind and workload_name labels, resolved through the pod's owner references, to the warmup outcome metrics")
+	flag.IntVar(&metricsMaxWorkloads, "metrics-max-workloads", controller.DefaultMaxMetricWorkloads, "Maximum number of distinct workloads labeled in metrics per controller instance; further workloads are labeled workload_name=\"other\" (0 = unlimited)")
+	flag.DurationVar(&watchdogInterval, "watchdog-interval", controller.DefaultWatchdogInterval, "How often the watchdog checks for stuck pods")
+	flag.BoolVar(&denyInvalidAnnotations, "deny-invalid-warmup-annotations", false, "Reject pods whose warmup annotations are invalid instead of admitting them with a warning (their warmup would be skipped)")
+	flag.StringVar(&signingKeyFile, "warmup-signing-key-file", "", "Path to a file containing the HMAC key used to sign warmup requests (empty = signing disabled)")
 
 	opts := zap.Options{
 		Development: true,
@@ -92,15 +126,28 @@ func main() {
 		"metrics-addr", metricsAddr,
 		"maxConcurrentWarmups", maxConcurrentWarmups,
 		"maxWarmupRPS", maxWarmupRPS,
+		"namespaceWeights", namespaceWeights,
+		"honorPodPriority", honorPodPriority,
+		"shutdownGracePeriod", shutdownGracePeriod,
+		"enableWatchdog", enableWatchdog,
+		"enableConfigStatus", enableConfigStatus,
+		"recordWarmupRuns", recordWarmupRuns,
+		"warmupRunRetention", warmupRunRetention,
+		"enableWarmupRunCleanup", enableWarmupRunCleanup,
 	)
 
+	// Give runnables time to drain warmups: the grace period itself, plus time to
+	// interrupt the remaining warmups and hand them off
+	gracefulShutdownTimeout := shutdownGracePeriod + 30*time.Second
+
 	// Build manager options
 	mgrOptions := ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "kube-booster.io",
+		Scheme:                  scheme,
+		Metrics:                 metricsserver.Options{BindAddress: metricsAddr},
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElection:          enableLeaderElection,
+		LeaderElectionID:        "kube-booster.io",
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
 	}
 
 	// Only configure webhook server if webhook is enabled
@@ -131,19 +178,37 @@ func main() {
 	// Create rate limiter (nil if maxWarmupRPS <= 0)
 	rateLimiter := warmup.NewRequestRateLimiter(float64(maxWarmupRPS))
 
+	// Create request signer (nil if no signing key is configured)
+	signer, err := loadSigner(signingKeyFile)
+	if err != nil {
+		setupLog.Error(err, "unable to load warmup signing key", "path", signingKeyFile)
+		os.Exit(1)
+	}
+	if signer != nil {
+		setupLog.Info("warmup request signing enabled", "path", signingKeyFile)
+	}
+
 	// Create warmup executor
 	warmupExecutor := warmup.NewWarmupExecutor(ctrl.Log.WithName("warmup"),
-		warmup.WithRateLimiter(rateLimiter))
+		warmup.WithRateLimiter(rateLimiter),
+		warmup.WithSigner(signer))
 
 	// Create scenario executor (for WarmupConfig CRD-based warmup)
 	scenarioExecutor := warmup.NewScenarioExecutor(ctrl.Log.WithName("scenario"),
-		warmup.WithScenarioRateLimiter(rateLimiter))
+		warmup.WithScenarioRateLimiter(rateLimiter),
+		warmup.WithScenarioSigner(signer))
 
-	// Create semaphore (nil if maxConcurrentWarmups <= 0, meaning unlimited)
-	var warmupSemaphore *semaphore.Weighted
-	if maxConcurrentWarmups > 0 {
-		warmupSemaphore = semaphore.NewWeighted(int64(maxConcurrentWarmups))
+	// Create fair scheduler (nil if maxConcurrentWarmups <= 0, meaning unlimited)
+	weights, err := controller.ParseNamespaceWeights(namespaceWeights)
+	if err != nil {
+		setupLog.Error(err, "invalid --namespace-weights")
+		os.Exit(1)
+	}
+	schedulerOpts := []controller.FairSchedulerOption{controller.WithNamespaceWeights(weights)}
+	if honorPodPriority {
+		schedulerOpts = append(schedulerOpts, controller.WithPodPriority())
 	}
+	warmupScheduler := controller.NewFairScheduler(maxConcurrentWarmups, schedulerOpts...)
 
 	// Setup pod controller (only if enabled)
 	if enableController {
@@ -153,7 +218,14 @@ func main() {
 			WarmupExecutor:   warmupExecutor,
 			ScenarioExecutor: scenarioExecutor,
 			Recorder:         mgr.GetEventRecorder("kube-booster-controller"),
-			WarmupSemaphore:  warmupSemaphore,
+			WarmupScheduler:  warmupScheduler,
+			WarmupPool:       controller.NewWarmupPool(controller.WithShutdownGracePeriod(shutdownGracePeriod)),
+
+			BestEffortMissingGate: bestEffortMissingGate,
+			RecordWarmupRuns:      recordWarmupRuns,
+			WarmupRunRetention:    warmupRunRetention,
+			Workloads:             &controller.WorkloadResolver{Reader: mgr.GetAPIReader(), MaxWorkloads: metricsMaxWorkloads},
+			WorkloadMetricLabels:  metricsWorkloadLabels,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "Pod")
 			os.Exit(1)
@@ -163,10 +235,58 @@ func main() {
 
 	// Setup webhook (only if enabled)
 	if enableWebhook {
+		podMutator := webhookpkg.NewPodMutator(mgr.GetClient(), mgr.GetScheme())
+		podMutator.Validate = warmup.ValidatePod
+		podMutator.DenyInvalid = denyInvalidAnnotations
 		mgr.GetWebhookServer().Register("/mutate-v1-pod", &webhook.Admission{
-			Handler: webhookpkg.NewPodMutator(mgr.GetClient(), mgr.GetScheme()),
+			Handler: podMutator,
 		})
 		setupLog.Info("registered webhook", "path", "/mutate-v1-pod")
+		mgr.GetWebhookServer().Register("/validate-warmupconfig", &webhook.Admission{
+			Handler: webhookpkg.NewWarmupConfigValidator(mgr.GetScheme(), warmup.ValidateWarmupConfigSpec),
+		})
+		setupLog.Info("registered webhook", "path", "/validate-warmupconfig")
+	}
+
+	// Setup WarmupConfig status reconciler (only if enabled)
+	if enableConfigStatus {
+		if err := (&controller.WarmupConfigStatusReconciler{
+			Client: mgr.GetClient(),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "WarmupConfigStatus")
+			os.Exit(1)
+		}
+		setupLog.Info("WarmupConfig status reconciler enabled")
+	}
+
+	// Setup readiness watchdog (only if enabled)
+	if enableWatchdog {
+		if watchdogMaxAge <= 0 || watchdogInterval <= 0 {
+			setupLog.Error(nil, "--watchdog-max-age and --watchdog-interval must be positive")
+			os.Exit(1)
+		}
+		if err := mgr.Add(&controller.ReadinessWatchdog{
+			Client:   mgr.GetClient(),
+			Recorder: mgr.GetEventRecorder("kube-booster-watchdog"),
+			MaxAge:   watchdogMaxAge,
+			Interval: watchdogInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up readiness watchdog")
+			os.Exit(1)
+		}
+		setupLog.Info("readiness watchdog enabled", "maxAge", watchdogMaxAge, "interval", watchdogInterval)
+	}
+
+	// Setup WarmupRun cleanup (only if enabled)
+	if enableWarmupRunCleanup {
+		if err := mgr.Add(&controller.WarmupRunCleaner{
+			Client:   mgr.GetClient(),
+			Interval: controller.DefaultWarmupRunCleanupInterval,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up WarmupRun cleanup")
+			os.Exit(1)
+		}
+		setupLog.Info("WarmupRun cleanup enabled", "interval", controller.DefaultWarmupRunCleanupInterval)
 	}
 
 	// Add health check endpoints
@@ -185,3 +305,25 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// loadSigner reads the warmup signing key from path. An empty path disables signing.
+// A missing file also disables signing (with a log message) so that the controller can
+// run with an optional Secret volume that has not been created yet.
+func loadSigner(path string) (*signing.Signer, error) {
+	if path == "" {
+		return nil, nil
+	}
+	key, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		setupLog.Info("warmup signing key file not found, signing disabled", "path", path)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	key = bytes.TrimSpace(key)
+	if len(key) == 0 {
+		return nil, fmt.Errorf("warmup signing key file %s is empty", path)
+	}
+	return signing.NewSigner(key), nil
+}
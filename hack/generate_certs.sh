@@ -85,18 +85,20 @@ echo "========================================================"
 CA_BUNDLE=$(base64 < "${CERT_DIR}/ca.crt" | tr -d '\n')
 echo "${CA_BUNDLE}"
 echo ""
-echo "Update config/webhook/mutating_webhook.yaml with this CA bundle:"
+echo "Update config/webhook/mutating_webhook.yaml and config/webhook/validating_webhook.yaml with this CA bundle:"
 echo "Replace \${CA_BUNDLE} with the above value"
 echo ""
 
 # Optionally update the webhook configuration automatically
 if command -v yq &> /dev/null; then
-  echo "Updating mutating_webhook.yaml with CA bundle..."
-  WEBHOOK_FILE="${SCRIPT_DIR}/../config/webhook/mutating_webhook.yaml"
-  # Use sed for simpler replacement
-  sed -i.bak "s|\${CA_BUNDLE}|${CA_BUNDLE}|g" "${WEBHOOK_FILE}"
-  echo "Updated ${WEBHOOK_FILE}"
-  echo "(Original backed up as ${WEBHOOK_FILE}.bak)"
+  for WEBHOOK_NAME in mutating_webhook.yaml validating_webhook.yaml; do
+    echo "Updating ${WEBHOOK_NAME} with CA bundle..."
+    WEBHOOK_FILE="${SCRIPT_DIR}/../config/webhook/${WEBHOOK_NAME}"
+    # Use sed for simpler replacement
+    sed -i.bak "s|\${CA_BUNDLE}|${CA_BUNDLE}|g" "${WEBHOOK_FILE}"
+    echo "Updated ${WEBHOOK_FILE}"
+    echo "(Original backed up as ${WEBHOOK_FILE}.bak)"
+  done
 else
   echo "Note: Install 'yq' to automatically update the webhook configuration"
 fi
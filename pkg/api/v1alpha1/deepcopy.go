@@ -1,6 +1,9 @@
 package v1alpha1
 
-import "k8s.io/apimachinery/pkg/runtime"
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
 
 // DeepCopyObject implements runtime.Object.
 func (in *WarmupConfig) DeepCopyObject() runtime.Object {
@@ -18,6 +21,7 @@ func (in *WarmupConfig) DeepCopyInto(out *WarmupConfig) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy returns a deep copy of WarmupConfig.
@@ -54,6 +58,72 @@ func (in *WarmupConfigList) DeepCopyInto(out *WarmupConfigList) {
 	}
 }
 
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterWarmupConfig) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWarmupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another ClusterWarmupConfig.
+func (in *ClusterWarmupConfig) DeepCopyInto(out *ClusterWarmupConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of ClusterWarmupConfig.
+func (in *ClusterWarmupConfig) DeepCopy() *ClusterWarmupConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWarmupConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterWarmupConfigList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWarmupConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another ClusterWarmupConfigList.
+func (in *ClusterWarmupConfigList) DeepCopyInto(out *ClusterWarmupConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterWarmupConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties into another ClusterWarmupConfigSpec.
+func (in *ClusterWarmupConfigSpec) DeepCopyInto(out *ClusterWarmupConfigSpec) {
+	*out = *in
+	in.WarmupConfigSpec.DeepCopyInto(&out.WarmupConfigSpec)
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		out.NamespaceSelector = in.NamespaceSelector.DeepCopy()
+	}
+}
+
 // DeepCopyInto copies all properties into another WarmupConfigSpec.
 func (in *WarmupConfigSpec) DeepCopyInto(out *WarmupConfigSpec) {
 	*out = *in
@@ -64,6 +134,21 @@ func (in *WarmupConfigSpec) DeepCopyInto(out *WarmupConfigSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.PersistVariables != nil {
+		in, out := &in.PersistVariables, &out.PersistVariables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Includes != nil {
+		in, out := &in.Includes, &out.Includes
+		*out = make([]WarmupConfigReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]WarmupParameter, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopyInto copies all properties into another WarmupStep.
@@ -76,6 +161,28 @@ func (in *WarmupStep) DeepCopyInto(out *WarmupStep) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Mix != nil {
+		in, out := &in.Mix, &out.Mix
+		*out = new(WarmupMix)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StepsFrom != nil {
+		in, out := &in.StepsFrom, &out.StepsFrom
+		*out = new(WarmupConfigReference)
+		**out = **in
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupMix.
+func (in *WarmupMix) DeepCopyInto(out *WarmupMix) {
+	*out = *in
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]WarmupRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopyInto copies all properties into another WarmupRequest.
@@ -95,4 +202,319 @@ func (in *WarmupRequest) DeepCopyInto(out *WarmupRequest) {
 			(*out)[k] = v
 		}
 	}
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]LoadStage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *WarmupPolicy) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another WarmupPolicy.
+func (in *WarmupPolicy) DeepCopyInto(out *WarmupPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of WarmupPolicy.
+func (in *WarmupPolicy) DeepCopy() *WarmupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *WarmupPolicyList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another WarmupPolicyList.
+func (in *WarmupPolicyList) DeepCopyInto(out *WarmupPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WarmupPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterWarmupPolicy) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWarmupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another ClusterWarmupPolicy.
+func (in *ClusterWarmupPolicy) DeepCopyInto(out *ClusterWarmupPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy returns a deep copy of ClusterWarmupPolicy.
+func (in *ClusterWarmupPolicy) DeepCopy() *ClusterWarmupPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWarmupPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *ClusterWarmupPolicyList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterWarmupPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another ClusterWarmupPolicyList.
+func (in *ClusterWarmupPolicyList) DeepCopyInto(out *ClusterWarmupPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterWarmupPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupPolicySpec.
+func (in *WarmupPolicySpec) DeepCopyInto(out *WarmupPolicySpec) {
+	*out = *in
+	in.PodSelector.DeepCopyInto(&out.PodSelector)
+	if in.NamespaceSelector != nil {
+		out.NamespaceSelector = in.NamespaceSelector.DeepCopy()
+	}
+	if in.Settings != nil {
+		out.Settings = new(WarmupPolicySettings)
+		*out.Settings = *in.Settings
+	}
+	if in.WarmupConfigRef != nil {
+		out.WarmupConfigRef = new(WarmupConfigReference)
+		*out.WarmupConfigRef = *in.WarmupConfigRef
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupConfigStatus.
+func (in *WarmupConfigStatus) DeepCopyInto(out *WarmupConfigStatus) {
+	*out = *in
+	if in.LastExecutionTime != nil {
+		out.LastExecutionTime = in.LastExecutionTime.DeepCopy()
+	}
+	if in.SuccessRate != nil {
+		in, out := &in.SuccessRate, &out.SuccessRate
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MeanDuration != nil {
+		in, out := &in.MeanDuration, &out.MeanDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StepFailures != nil {
+		in, out := &in.StepFailures, &out.StepFailures
+		*out = make([]StepFailureCount, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of WarmupConfigStatus.
+func (in *WarmupConfigStatus) DeepCopy() *WarmupConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *WarmupRun) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another WarmupRun.
+func (in *WarmupRun) DeepCopyInto(out *WarmupRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy returns a deep copy of WarmupRun.
+func (in *WarmupRun) DeepCopy() *WarmupRun {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object.
+func (in *WarmupRunList) DeepCopyObject() runtime.Object {
+	if in == nil {
+		return nil
+	}
+	out := new(WarmupRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties into another WarmupRunList.
+func (in *WarmupRunList) DeepCopyInto(out *WarmupRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]WarmupRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupRunSpec.
+func (in *WarmupRunSpec) DeepCopyInto(out *WarmupRunSpec) {
+	*out = *in
+	if in.RetainUntil != nil {
+		out.RetainUntil = in.RetainUntil.DeepCopy()
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupRunStatus.
+func (in *WarmupRunStatus) DeepCopyInto(out *WarmupRunStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.EndTime.DeepCopyInto(&out.EndTime)
+	if in.LatencyP50 != nil {
+		in, out := &in.LatencyP50, &out.LatencyP50
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LatencyP99 != nil {
+		in, out := &in.LatencyP99, &out.LatencyP99
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FirstRequestLatency != nil {
+		in, out := &in.FirstRequestLatency, &out.FirstRequestLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FinalWindowLatency != nil {
+		in, out := &in.FinalWindowLatency, &out.FinalWindowLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]WarmupRunStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupRunStep.
+func (in *WarmupRunStep) DeepCopyInto(out *WarmupRunStep) {
+	*out = *in
+	if in.LatencyP50 != nil {
+		in, out := &in.LatencyP50, &out.LatencyP50
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LatencyP99 != nil {
+		in, out := &in.LatencyP99, &out.LatencyP99
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make([]WarmupRunRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties into another WarmupRunRequest.
+func (in *WarmupRunRequest) DeepCopyInto(out *WarmupRunRequest) {
+	*out = *in
+	if in.LatencyP50 != nil {
+		in, out := &in.LatencyP50, &out.LatencyP50
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LatencyP99 != nil {
+		in, out := &in.LatencyP99, &out.LatencyP99
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.FirstLatency != nil {
+		in, out := &in.FirstLatency, &out.FirstLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.StatusCodes != nil {
+		in, out := &in.StatusCodes, &out.StatusCodes
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
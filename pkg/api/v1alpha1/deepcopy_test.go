@@ -1,6 +1,10 @@
 package v1alpha1
 
-import "testing"
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 func TestWarmupConfig_DeepCopy_isolatesSlices(t *testing.T) {
 	orig := &WarmupConfig{
@@ -62,3 +66,153 @@ func TestWarmupConfigSpec_DeepCopyInto_mapsIsolated(t *testing.T) {
 		t.Error("DeepCopyInto shared Extract map with original")
 	}
 }
+
+func TestWarmupStep_DeepCopyInto_mixIsolated(t *testing.T) {
+	orig := WarmupStep{
+		Mix: &WarmupMix{
+			TotalRequests: 10,
+			Requests:      []WarmupRequest{{Endpoint: "/browse", Weight: 6}},
+		},
+	}
+	var cp WarmupStep
+	orig.DeepCopyInto(&cp)
+
+	cp.Mix.TotalRequests = 99
+	cp.Mix.Requests[0].Weight = 1
+
+	if orig.Mix.TotalRequests != 10 {
+		t.Error("DeepCopyInto shared Mix pointer with original")
+	}
+	if orig.Mix.Requests[0].Weight != 6 {
+		t.Error("DeepCopyInto shared Mix.Requests slice with original")
+	}
+}
+
+func TestWarmupPolicySpec_DeepCopyInto_pointersIsolated(t *testing.T) {
+	orig := &WarmupPolicy{
+		Spec: WarmupPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+			Settings:          &WarmupPolicySettings{Endpoint: "/warm"},
+			WarmupConfigRef:   &WarmupConfigReference{Name: "cfg"},
+		},
+	}
+	orig.Spec.PodSelector.MatchLabels = map[string]string{"app": "web"}
+	cp := orig.DeepCopy()
+
+	cp.Spec.PodSelector.MatchLabels["app"] = "mutated"
+	cp.Spec.NamespaceSelector.MatchLabels["team"] = "mutated"
+	cp.Spec.Settings.Endpoint = "/mutated"
+	cp.Spec.WarmupConfigRef.Name = "mutated"
+
+	if orig.Spec.PodSelector.MatchLabels["app"] != "web" {
+		t.Error("DeepCopy shared PodSelector labels with original")
+	}
+	if orig.Spec.NamespaceSelector.MatchLabels["team"] != "a" {
+		t.Error("DeepCopy shared NamespaceSelector with original")
+	}
+	if orig.Spec.Settings.Endpoint != "/warm" {
+		t.Error("DeepCopy shared Settings with original")
+	}
+	if orig.Spec.WarmupConfigRef.Name != "cfg" {
+		t.Error("DeepCopy shared WarmupConfigRef with original")
+	}
+}
+
+func TestClusterWarmupConfig_DeepCopy_isolatesAllowList(t *testing.T) {
+	orig := &ClusterWarmupConfig{
+		Spec: ClusterWarmupConfigSpec{
+			WarmupConfigSpec:  WarmupConfigSpec{Steps: []WarmupStep{{Name: "s1"}}},
+			AllowedNamespaces: []string{"team-a"},
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+		},
+	}
+	cp := orig.DeepCopy()
+
+	cp.Spec.Steps[0].Name = "mutated"
+	cp.Spec.AllowedNamespaces[0] = "mutated"
+	cp.Spec.NamespaceSelector.MatchLabels["tier"] = "mutated"
+
+	if orig.Spec.Steps[0].Name != "s1" {
+		t.Error("DeepCopy shared Steps slice with original")
+	}
+	if orig.Spec.AllowedNamespaces[0] != "team-a" {
+		t.Error("DeepCopy shared AllowedNamespaces with original")
+	}
+	if orig.Spec.NamespaceSelector.MatchLabels["tier"] != "web" {
+		t.Error("DeepCopy shared NamespaceSelector with original")
+	}
+}
+
+func TestWarmupConfigSpec_DeepCopyInto_referencesIsolated(t *testing.T) {
+	orig := WarmupConfigSpec{
+		Includes: []WarmupConfigReference{{Kind: "ClusterWarmupConfig", Name: "auth"}},
+		Steps:    []WarmupStep{{StepsFrom: &WarmupConfigReference{Name: "health"}}},
+	}
+	var cp WarmupConfigSpec
+	orig.DeepCopyInto(&cp)
+
+	cp.Includes[0].Name = "mutated"
+	cp.Steps[0].StepsFrom.Name = "mutated"
+
+	if orig.Includes[0].Name != "auth" {
+		t.Error("DeepCopyInto shared Includes slice with original")
+	}
+	if orig.Steps[0].StepsFrom.Name != "health" {
+		t.Error("DeepCopyInto shared StepsFrom with original")
+	}
+}
+
+func TestWarmupConfig_DeepCopy_statusIsolated(t *testing.T) {
+	rate := int32(90)
+	orig := &WarmupConfig{Status: WarmupConfigStatus{
+		SuccessRate:  &rate,
+		StepFailures: []StepFailureCount{{Step: "login", Failures: 1}},
+		Conditions:   []metav1.Condition{{Type: WarmupConfigConditionValid, Status: metav1.ConditionTrue}},
+	}}
+	cp := orig.DeepCopy()
+
+	*cp.Status.SuccessRate = 10
+	cp.Status.StepFailures[0].Failures = 5
+	cp.Status.Conditions[0].Status = metav1.ConditionFalse
+
+	if *orig.Status.SuccessRate != 90 {
+		t.Error("DeepCopy shared SuccessRate with original")
+	}
+	if orig.Status.StepFailures[0].Failures != 1 {
+		t.Error("DeepCopy shared StepFailures slice with original")
+	}
+	if orig.Status.Conditions[0].Status != metav1.ConditionTrue {
+		t.Error("DeepCopy shared Conditions slice with original")
+	}
+}
+
+func TestWarmupRun_DeepCopy_stepsIsolated(t *testing.T) {
+	orig := &WarmupRun{Status: WarmupRunStatus{
+		LatencyP50: &metav1.Duration{Duration: 5},
+		Steps: []WarmupRunStep{{Name: "login", Requests: []WarmupRunRequest{{
+			Name:              "login/req-1",
+			RequestsCompleted: 1,
+			StatusCodes:       map[string]int32{"200": 1},
+			Errors:            []string{"connection refused"},
+		}}}},
+	}}
+	cp := orig.DeepCopyObject().(*WarmupRun)
+
+	cp.Status.LatencyP50.Duration = 10
+	cp.Status.Steps[0].Requests[0].RequestsCompleted = 9
+	cp.Status.Steps[0].Requests[0].StatusCodes["200"] = 9
+	cp.Status.Steps[0].Requests[0].Errors[0] = "timeout"
+
+	if orig.Status.LatencyP50.Duration != 5 {
+		t.Error("DeepCopy shared LatencyP50 with original")
+	}
+	if orig.Status.Steps[0].Requests[0].RequestsCompleted != 1 {
+		t.Error("DeepCopy shared step Requests slice with original")
+	}
+	if orig.Status.Steps[0].Requests[0].StatusCodes["200"] != 1 {
+		t.Error("DeepCopy shared request StatusCodes map with original")
+	}
+	if orig.Status.Steps[0].Requests[0].Errors[0] != "connection refused" {
+		t.Error("DeepCopy shared request Errors slice with original")
+	}
+}
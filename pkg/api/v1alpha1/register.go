@@ -15,7 +15,13 @@ var (
 )
 
 func addKnownTypes(scheme *runtime.Scheme) error {
-	scheme.AddKnownTypes(SchemeGroupVersion, &WarmupConfig{}, &WarmupConfigList{})
+	scheme.AddKnownTypes(SchemeGroupVersion,
+		&WarmupConfig{}, &WarmupConfigList{},
+		&ClusterWarmupConfig{}, &ClusterWarmupConfigList{},
+		&WarmupPolicy{}, &WarmupPolicyList{},
+		&ClusterWarmupPolicy{}, &ClusterWarmupPolicyList{},
+		&WarmupRun{}, &WarmupRunList{},
+	)
 	return nil
 }
 
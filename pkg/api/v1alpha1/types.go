@@ -10,6 +10,9 @@ type WarmupConfig struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec WarmupConfigSpec `json:"spec"`
+
+	// +optional
+	Status WarmupConfigStatus `json:"status,omitempty"`
 }
 
 // WarmupConfigList contains a list of WarmupConfig.
@@ -20,35 +23,210 @@ type WarmupConfigList struct {
 	Items []WarmupConfig `json:"items"`
 }
 
+// ClusterWarmupConfig is a cluster-scoped WarmupConfig that can be shared across
+// namespaces. A pod selects it by setting the kube-booster.io/warmup-config
+// annotation to "ClusterWarmupConfig/<name>". It is only usable from the
+// namespaces it allows.
+type ClusterWarmupConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ClusterWarmupConfigSpec `json:"spec"`
+}
+
+// ClusterWarmupConfigList contains a list of ClusterWarmupConfig.
+type ClusterWarmupConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterWarmupConfig `json:"items"`
+}
+
+// ClusterWarmupConfigSpec is the desired state of a ClusterWarmupConfig: a
+// WarmupConfigSpec plus the namespaces allowed to use it. A namespace is allowed if
+// it is listed in AllowedNamespaces or matches NamespaceSelector. When neither is
+// set, no namespace may use the config.
+type ClusterWarmupConfigSpec struct {
+	WarmupConfigSpec `json:",inline"`
+
+	// AllowedNamespaces lists the namespaces whose pods may use this config.
+	// +optional
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+
+	// NamespaceSelector allows every namespace whose labels match. An empty
+	// selector allows every namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+}
+
 // WarmupConfigSpec is the desired state of a WarmupConfig.
 type WarmupConfigSpec struct {
 	// Steps is the ordered list of warmup steps. Steps are executed sequentially.
+	// It may be omitted when Includes is set.
 	// +kubebuilder:validation:MinItems=1
-	Steps []WarmupStep `json:"steps"`
+	// +optional
+	Steps []WarmupStep `json:"steps,omitempty"`
 
 	// Timeout is the overall time limit for all steps combined.
 	// Parsed as a Go duration string (e.g. "120s", "2m"). Default: "120s".
 	// +optional
 	Timeout string `json:"timeout,omitempty"`
+
+	// RateLimit caps the request rate (requests per second) of each warmup run that
+	// uses this config. It applies on top of the controller-wide --max-warmup-rps
+	// limit; when the pod also sets kube-booster.io/warmup-rps, the lower rate wins.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RateLimit int `json:"rateLimit,omitempty"`
+
+	// PersistVariables lists session variables that may be saved in the pod's
+	// kube-booster.io/warmup-progress annotation when the controller restarts
+	// mid-scenario, so that the next attempt can skip the steps that extracted them.
+	// Pod annotations are readable by anyone who can read the pod, so only list
+	// values that are not secret. Steps whose extracted variables are not listed
+	// are run again on resume.
+	// +optional
+	PersistVariables []string `json:"persistVariables,omitempty"`
+
+	// Includes lists other configs whose steps run before this config's own steps,
+	// in order. Included configs may include further configs, up to 5 levels deep.
+	// Only their steps, parameters and persisted variables are used; Timeout and
+	// RateLimit come from the config the pod references.
+	// +kubebuilder:validation:MaxItems=10
+	// +optional
+	Includes []WarmupConfigReference `json:"includes,omitempty"`
+
+	// Parameters declares values that each pod using this config may set, so that a
+	// shared scenario can differ per workload. Pods set them with the
+	// kube-booster.io/warmup-config-params JSON annotation or with
+	// kube-booster.io/param.<name> annotations, and requests reference them as
+	// {{params.<name>}}.
+	// +optional
+	Parameters []WarmupParameter `json:"parameters,omitempty"`
+}
+
+// WarmupConfigConditionValid is the condition type that reports whether the
+// WarmupConfig passes the checks of the validating webhook.
+const WarmupConfigConditionValid = "Valid"
+
+// WarmupConfigStatus is the observed state of a WarmupConfig. It is maintained by
+// the WarmupConfig status reconciler from the spec and from the warmup results the
+// node-local controllers record on the pods that use the config.
+type WarmupConfigStatus struct {
+	// ObservedGeneration is the generation of the spec that Conditions describe.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Pods is the number of existing pods whose warmup uses the config, directly or
+	// through an include, whether they reference it themselves or inherit the
+	// reference from a policy or their namespace.
+	// +optional
+	Pods int32 `json:"pods"`
+
+	// LastExecutionTime is when a warmup using the config last finished.
+	// +optional
+	LastExecutionTime *metav1.Time `json:"lastExecutionTime,omitempty"`
+
+	// CurrentPodRuns is the number of warmup runs the statistics below are computed
+	// from: the most recent runs (at most 50) of the pods that currently exist. Runs
+	// of deleted pods are not kept.
+	// +optional
+	CurrentPodRuns int32 `json:"currentPodRuns,omitempty"`
+
+	// SuccessRate is the percentage (0-100) of CurrentPodRuns that succeeded.
+	// +optional
+	SuccessRate *int32 `json:"successRate,omitempty"`
+
+	// MeanDuration is the mean duration of CurrentPodRuns.
+	// +optional
+	MeanDuration *metav1.Duration `json:"meanDuration,omitempty"`
+
+	// StepFailures counts, for each step that failed, the CurrentPodRuns in which it
+	// had failed requests, in scenario order.
+	// +optional
+	StepFailures []StepFailureCount `json:"stepFailures,omitempty"`
+
+	// Conditions include Valid, which reports the result of the checks the
+	// validating webhook applies, for configs created while it was unavailable.
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// StepFailureCount is the number of warmup runs of current pods in which a step
+// failed.
+type StepFailureCount struct {
+	// Step is the step's name, or "step-<n>" if it has none.
+	Step string `json:"step"`
+
+	// Failures is the number of runs in which the step had failed requests.
+	Failures int32 `json:"failures"`
+}
+
+// WarmupParameter declares a parameter of a WarmupConfig.
+type WarmupParameter struct {
+	// Name is referenced as {{params.<name>}} and set by the kube-booster.io/param.<name>
+	// annotation.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_-]*$`
+	Name string `json:"name"`
+
+	// Default is used when the pod does not set the parameter.
+	// +optional
+	Default string `json:"default,omitempty"`
+
+	// Required fails the warmup of pods that do not set the parameter. Required
+	// parameters have no default.
+	// +optional
+	Required bool `json:"required,omitempty"`
 }
 
 // WarmupStep groups one or more requests that are executed as a unit.
+// Exactly one of Requests, Mix or StepsFrom must be set.
 type WarmupStep struct {
 	// Name is an optional human-readable label for the step (used in logs and events).
 	// +optional
 	Name string `json:"name,omitempty"`
 
+	// StepsFrom replaces this step with the steps of another config, so that shared
+	// blocks can be placed anywhere in a scenario. Name and Timeout are ignored.
+	// +optional
+	StepsFrom *WarmupConfigReference `json:"stepsFrom,omitempty"`
+
 	// Requests is the list of warmup requests executed within this step. Requests
 	// are executed sequentially in the order they appear.
-	// +kubebuilder:validation:MinItems=1
-	Requests []WarmupRequest `json:"requests"`
+	// +optional
+	Requests []WarmupRequest `json:"requests,omitempty"`
+
+	// Mix turns the step into a weighted traffic profile: requests are sampled at
+	// random in proportion to their Weight instead of being executed in order.
+	// +optional
+	Mix *WarmupMix `json:"mix,omitempty"`
 
 	// Timeout is the time limit for this step. Parsed as a Go duration string.
-	// Default: "30s".
+	// Default: "30s", or the mix duration or the total duration of the requests'
+	// load stages plus 10s when that is longer.
 	// +optional
 	Timeout string `json:"timeout,omitempty"`
 }
 
+// WarmupMix describes a weighted request mix (e.g. 60% browse, 30% search,
+// 10% checkout). The mix runs until TotalRequests have been sent or Duration has
+// elapsed, whichever comes first. When neither is set, 100 requests are sent.
+type WarmupMix struct {
+	// Requests is the set of requests to sample from. Each request's Weight sets its
+	// relative share of traffic; Count is ignored.
+	// +kubebuilder:validation:MinItems=1
+	Requests []WarmupRequest `json:"requests"`
+
+	// TotalRequests is the total number of requests to send across the mix.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TotalRequests int `json:"totalRequests,omitempty"`
+
+	// Duration is the time budget for the mix. Parsed as a Go duration string.
+	// +optional
+	Duration string `json:"duration,omitempty"`
+}
+
 // WarmupRequest describes a single warmup call within a step.
 type WarmupRequest struct {
 	// Name is an optional label used in log output.
@@ -96,10 +274,23 @@ type WarmupRequest struct {
 	GRPCPayload string `json:"grpcPayload,omitempty"`
 
 	// Count is the number of times to repeat this request. Default: 1.
+	// Ignored within a mix.
 	// +kubebuilder:validation:Minimum=1
 	// +optional
 	Count int `json:"count,omitempty"`
 
+	// Stages paces this request through a ramp-up load schedule (e.g. 5 rps for 10s,
+	// then 20 rps for 10s). When set, the request is repeated until the final stage
+	// ends and Count is ignored. Ignored within a mix.
+	// +optional
+	Stages []LoadStage `json:"stages,omitempty"`
+
+	// Weight is the relative share of traffic this request receives within a mix.
+	// Ignored outside a mix. Default: 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Weight int `json:"weight,omitempty"`
+
 	// Extract maps session variable names to simple JSONPath expressions
 	// (e.g. "$.token" or "$.nested.key"). The value is extracted from the last
 	// response body and stored in the session for use by subsequent requests via
@@ -114,4 +305,322 @@ type WarmupRequest struct {
 	// Ignored for gRPC requests.
 	// +optional
 	ExpectedStatus int `json:"expectedStatus,omitempty"`
+
+	// ExpectedGRPCCode is the name of the gRPC status code that counts as success
+	// (e.g. "NOT_FOUND"). Any other code increments RequestsFailed. Default: "OK".
+	// Ignored for HTTP requests.
+	// +kubebuilder:validation:Enum=OK;CANCELLED;UNKNOWN;INVALID_ARGUMENT;DEADLINE_EXCEEDED;NOT_FOUND;ALREADY_EXISTS;PERMISSION_DENIED;RESOURCE_EXHAUSTED;FAILED_PRECONDITION;ABORTED;OUT_OF_RANGE;UNIMPLEMENTED;INTERNAL;UNAVAILABLE;DATA_LOSS;UNAUTHENTICATED
+	// +optional
+	ExpectedGRPCCode string `json:"expectedGRPCCode,omitempty"`
+}
+
+// LoadStage is a period of constant request rate within a ramp-up schedule.
+type LoadStage struct {
+	// RPS is the request rate during this stage.
+	// +kubebuilder:validation:Minimum=1
+	RPS int `json:"rps"`
+
+	// Duration is how long this stage lasts. Parsed as a Go duration string.
+	Duration string `json:"duration"`
+}
+
+// WarmupPolicy enables warmup for the pods in its namespace that match its pod
+// selector, without changes to the pods' manifests. Pod annotations still take
+// precedence over the settings of a policy.
+type WarmupPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WarmupPolicySpec `json:"spec"`
+}
+
+// WarmupPolicyList contains a list of WarmupPolicy.
+type WarmupPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []WarmupPolicy `json:"items"`
+}
+
+// ClusterWarmupPolicy is the cluster-scoped variant of WarmupPolicy. It applies to
+// pods in every namespace selected by its namespace selector.
+type ClusterWarmupPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WarmupPolicySpec `json:"spec"`
+}
+
+// ClusterWarmupPolicyList contains a list of ClusterWarmupPolicy.
+type ClusterWarmupPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ClusterWarmupPolicy `json:"items"`
+}
+
+// WarmupPolicySpec is the desired state of a WarmupPolicy or ClusterWarmupPolicy.
+// Exactly one of Settings or WarmupConfigRef must be set.
+type WarmupPolicySpec struct {
+	// PodSelector selects the pods the policy applies to. An empty selector
+	// selects every pod.
+	PodSelector metav1.LabelSelector `json:"podSelector"`
+
+	// NamespaceSelector selects the namespaces a ClusterWarmupPolicy applies to.
+	// When omitted, the policy applies in every namespace. Ignored for WarmupPolicy,
+	// which only applies in its own namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Priority decides between several policies that select the same pod: the
+	// highest priority wins. On a tie, a WarmupPolicy wins over a
+	// ClusterWarmupPolicy, and then the policy whose name sorts first.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Port is the container port for warmup requests. When omitted, the port is
+	// auto-detected as for annotated pods.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int `json:"port,omitempty"`
+
+	// Settings configures a single-endpoint warmup inline.
+	// +optional
+	Settings *WarmupPolicySettings `json:"settings,omitempty"`
+
+	// WarmupConfigRef runs the scenario of a WarmupConfig in the pod's namespace,
+	// or of a ClusterWarmupConfig that allows the pod's namespace.
+	// +optional
+	WarmupConfigRef *WarmupConfigReference `json:"warmupConfigRef,omitempty"`
+}
+
+// WarmupPolicySettings are the inline warmup settings of a policy. Each field
+// corresponds to the pod annotation of the same name and has the same defaults.
+type WarmupPolicySettings struct {
+	// Endpoint is the HTTP endpoint path for warmup requests.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Requests is the number of warmup requests to send.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=12000
+	// +optional
+	Requests int `json:"requests,omitempty"`
+
+	// Timeout is the maximum time for the warmup. Parsed as a Go duration string.
+	// +optional
+	Timeout string `json:"timeout,omitempty"`
+
+	// Protocol selects the warmup transport: "http" (default) or "grpc".
+	// +kubebuilder:validation:Enum=http;grpc
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// GRPCMethod is the fully-qualified gRPC method ("package.Service/Method").
+	// Required when Protocol is "grpc".
+	// +optional
+	GRPCMethod string `json:"grpcMethod,omitempty"`
+
+	// GRPCPayload is the JSON-encoded request message for gRPC warmup.
+	// +optional
+	GRPCPayload string `json:"grpcPayload,omitempty"`
+}
+
+// WarmupConfigReference refers to a WarmupConfig or ClusterWarmupConfig by name.
+type WarmupConfigReference struct {
+	// Kind is "WarmupConfig" (default) or "ClusterWarmupConfig".
+	// +kubebuilder:validation:Enum=WarmupConfig;ClusterWarmupConfig
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name is the name of the WarmupConfig in the pod's namespace, or of the
+	// ClusterWarmupConfig.
+	Name string `json:"name"`
+}
+
+// WarmupRun records a single warmup execution of a pod. The node-local controller
+// creates one when a warmup finishes, with the pod's labels, so that the history
+// outlives the pod's events. It is owned by the pod and deleted with it, unless the
+// controller is configured to retain runs (see WarmupRunSpec.RetainUntil).
+//
+// A WarmupRun is written once and has no status subresource: the outcome is
+// recorded in Status together with the Spec in a single create.
+type WarmupRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec WarmupRunSpec `json:"spec"`
+
+	// +optional
+	Status WarmupRunStatus `json:"status,omitempty"`
+}
+
+// WarmupRunList contains a list of WarmupRun.
+type WarmupRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []WarmupRun `json:"items"`
+}
+
+// WarmupRunSpec identifies the pod and the configuration a WarmupRun warmed up.
+type WarmupRunSpec struct {
+	// PodName is the name of the pod that was warmed up.
+	PodName string `json:"podName"`
+
+	// PodUID is the UID of the pod that was warmed up.
+	PodUID string `json:"podUID"`
+
+	// NodeName is the node the pod ran on.
+	// +optional
+	NodeName string `json:"nodeName,omitempty"`
+
+	// Attempt is the warmup attempt number; it is greater than 1 when an earlier
+	// attempt was interrupted by a controller restart.
+	Attempt int32 `json:"attempt"`
+
+	// Source is the configuration the warmup used.
+	Source WarmupRunSource `json:"source"`
+
+	// RetainUntil is set on runs that are kept after their pod is deleted. Such runs
+	// have no owner reference and are deleted once this time has passed.
+	// +optional
+	RetainUntil *metav1.Time `json:"retainUntil,omitempty"`
+}
+
+// WarmupRunSource describes where the configuration of a warmup came from.
+type WarmupRunSource struct {
+	// Kind is "Annotations" for single-endpoint warmup configured by annotations,
+	// or "WarmupConfig" or "ClusterWarmupConfig" for scenario warmup.
+	Kind string `json:"kind"`
+
+	// Name is the name of the WarmupConfig or ClusterWarmupConfig.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Settings lists the effective warmup settings and whether each was set by the
+	// pod, a policy, the namespace, or a default.
+	// +optional
+	Settings string `json:"settings,omitempty"`
+}
+
+// WarmupRunStatus is the outcome of a warmup execution.
+type WarmupRunStatus struct {
+	// StartTime is when the warmup started, after any wait for a concurrency slot.
+	StartTime metav1.Time `json:"startTime"`
+
+	// EndTime is when the warmup finished.
+	EndTime metav1.Time `json:"endTime"`
+
+	// Duration is the time the warmup requests took.
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// Success reports whether the warmup succeeded. The pod is marked ready either way.
+	Success bool `json:"success"`
+
+	// Message is the summary also used in the WarmupCompleted or WarmupFailed event.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// Error is the error that ended or failed the warmup, if any.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// RequestsCompleted is the number of requests that succeeded.
+	// +optional
+	RequestsCompleted int32 `json:"requestsCompleted,omitempty"`
+
+	// RequestsFailed is the number of requests that failed.
+	// +optional
+	RequestsFailed int32 `json:"requestsFailed,omitempty"`
+
+	// LatencyP50 is the median request latency, when measured.
+	// +optional
+	LatencyP50 *metav1.Duration `json:"latencyP50,omitempty"`
+
+	// LatencyP99 is the 99th percentile request latency, when measured.
+	// +optional
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+
+	// FirstRequestLatency is the latency of the first response, which usually pays
+	// for the application's cold start.
+	// +optional
+	FirstRequestLatency *metav1.Duration `json:"firstRequestLatency,omitempty"`
+
+	// FinalWindowLatency is the mean latency of the last responses. Divided into
+	// FirstRequestLatency, it gives the speedup the warmup achieved.
+	// +optional
+	FinalWindowLatency *metav1.Duration `json:"finalWindowLatency,omitempty"`
+
+	// Steps reports each WarmupConfig step that ran, in order.
+	// +optional
+	Steps []WarmupRunStep `json:"steps,omitempty"`
+}
+
+// WarmupRunStep is the outcome of a WarmupConfig step.
+type WarmupRunStep struct {
+	// Name is the step's name, or "step-<n>" if it has none.
+	Name string `json:"name"`
+
+	// RequestsCompleted is the number of the step's requests that succeeded.
+	// +optional
+	RequestsCompleted int32 `json:"requestsCompleted,omitempty"`
+
+	// RequestsFailed is the number of the step's requests that failed.
+	// +optional
+	RequestsFailed int32 `json:"requestsFailed,omitempty"`
+
+	// Duration is the time the step took.
+	// +optional
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// LatencyP50 is the median latency of the step's responses.
+	// +optional
+	LatencyP50 *metav1.Duration `json:"latencyP50,omitempty"`
+
+	// LatencyP99 is the 99th percentile latency of the step's responses.
+	// +optional
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+
+	// Requests reports each of the step's requests.
+	// +optional
+	Requests []WarmupRunRequest `json:"requests,omitempty"`
+}
+
+// WarmupRunRequest is the outcome of a request of a WarmupConfig step, over all the
+// times it was sent.
+type WarmupRunRequest struct {
+	// Name is the request's name, or "<step>/req-<n>" if it has none.
+	Name string `json:"name"`
+
+	// RequestsCompleted is the number of times the request succeeded.
+	// +optional
+	RequestsCompleted int32 `json:"requestsCompleted,omitempty"`
+
+	// RequestsFailed is the number of times the request failed.
+	// +optional
+	RequestsFailed int32 `json:"requestsFailed,omitempty"`
+
+	// LatencyP50 is the median latency of the request's responses.
+	// +optional
+	LatencyP50 *metav1.Duration `json:"latencyP50,omitempty"`
+
+	// LatencyP99 is the 99th percentile latency of the request's responses.
+	// +optional
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+
+	// FirstLatency is the latency of the first response to the request.
+	// +optional
+	FirstLatency *metav1.Duration `json:"firstLatency,omitempty"`
+
+	// StatusCodes counts the request's responses by status code: the HTTP status
+	// code, or the gRPC code name (e.g. "UNAVAILABLE").
+	// +optional
+	StatusCodes map[string]int32 `json:"statusCodes,omitempty"`
+
+	// Errors lists the distinct errors of the requests that got no response.
+	// +optional
+	Errors []string `json:"errors,omitempty"`
 }
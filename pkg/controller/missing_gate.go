@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+// missingGateTracker remembers the pods found requesting warmup without the
+// readiness gate, so that each pod is reported, and optionally warmed up, only
+// once. The zero value is ready to use.
+type missingGateTracker struct {
+	mu   sync.Mutex
+	pods map[types.NamespacedName]*missingGatePod
+}
+
+type missingGatePod struct {
+	uid         types.UID
+	warmupStart bool
+}
+
+// get returns the state for the pod, resetting it if the name now belongs to a
+// different pod. created reports whether the pod was not tracked before.
+// Must be called with t.mu held.
+func (t *missingGateTracker) get(pod *corev1.Pod) (state *missingGatePod, created bool) {
+	key := client.ObjectKeyFromObject(pod)
+	if state, ok := t.pods[key]; ok && state.uid == pod.UID {
+		return state, false
+	}
+	if t.pods == nil {
+		t.pods = map[types.NamespacedName]*missingGatePod{}
+	}
+	state = &missingGatePod{uid: pod.UID}
+	t.pods[key] = state
+	return state, true
+}
+
+// report records the pod and reports whether it had not been seen before.
+func (t *missingGateTracker) report(pod *corev1.Pod) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, created := t.get(pod)
+	return created
+}
+
+// startWarmup reports whether a best-effort warmup has not yet been started for
+// the pod, and records that one is starting.
+func (t *missingGateTracker) startWarmup(pod *corev1.Pod) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state, _ := t.get(pod)
+	if state.warmupStart {
+		return false
+	}
+	state.warmupStart = true
+	return true
+}
+
+// forget stops tracking a pod that has been deleted.
+func (t *missingGateTracker) forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pods, key)
+}
+
+// reconcileMissingGate handles a pod that requests warmup but has no readiness gate.
+// The pod is reported once; if best-effort warmup is enabled, it is also warmed up
+// once its containers are ready, without holding back its readiness.
+func (r *PodReconciler) reconcileMissingGate(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if r.missingGates.report(pod) {
+		logger.Info("pod requests warmup but has no readiness gate")
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupGateMissing, "CheckReadinessGate",
+			"Pod requests warmup but was created without the %s readiness gate (was the admission webhook unavailable?); "+
+				"readiness is not held back for warmup. Recreate the pod to enable it", webhook.ReadinessGateName)
+		metrics.RecordWarmupGateMissing(pod.Namespace)
+	}
+
+	if !r.BestEffortMissingGate {
+		return ctrl.Result{}, nil
+	}
+	if r.WarmupPool != nil {
+		if job := r.WarmupPool.Get(pod.UID); job != nil {
+			if _, done := job.finished(); done {
+				r.WarmupPool.Forget(pod.UID, job)
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+	if pod.Status.Phase != corev1.PodRunning || !r.areContainersReady(pod) {
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if !r.missingGates.startWarmup(pod) {
+		return ctrl.Result{}, nil
+	}
+
+	run := func(ctx context.Context) *warmupOutcome {
+		outcome := r.runWarmup(ctx, pod)
+		if outcome != nil && !outcome.configError {
+			r.recordBestEffortResult(ctx, pod, outcome)
+		}
+		return outcome
+	}
+	if r.WarmupPool != nil {
+		r.WarmupPool.Submit(ctx, pod, run)
+		return ctrl.Result{}, nil
+	}
+	run(ctx)
+	return ctrl.Result{}, nil
+}
+
+// recordBestEffortResult logs and emits the result event of a best-effort warmup.
+func (r *PodReconciler) recordBestEffortResult(ctx context.Context, pod *corev1.Pod, outcome *warmupOutcome) {
+	logger := log.FromContext(ctx)
+	result := outcome.result
+	if result.Success {
+		logger.Info("best-effort warmup completed", "message", result.Message)
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupCompleted, "CompleteWarmup",
+			"Best-effort warmup (no readiness gate): %s", result.Message)
+		return
+	}
+	logger.Info("best-effort warmup failed", "message", result.Message, "error", result.Error)
+	r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupFailed, "FailWarmup",
+		"Best-effort warmup (no readiness gate) failed: %s", result.Message)
+}
@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestPodReconciler_MissingGate(t *testing.T) {
+	tests := []struct {
+		name       string
+		bestEffort bool
+		// namespaceOptIn enables warmup through the namespace instead of the pod annotation
+		namespaceOptIn bool
+		wantCalls      int
+	}{
+		{name: "reported only"},
+		{name: "best-effort warmup", bestEffort: true, wantCalls: 1},
+		{name: "enabled by namespace", namespaceOptIn: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics.WarmupGateMissingTotal.Reset()
+
+			pod := makeReadyPod("no-gate", "default", nil)
+			pod.UID = "uid-1"
+			pod.Spec.ReadinessGates = nil
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+			if tt.namespaceOptIn {
+				delete(pod.Annotations, webhook.AnnotationWarmupEnabled)
+				ns.Labels = map[string]string{webhook.AnnotationWarmupEnabled: webhook.WarmupEnabledValue}
+			}
+
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+			_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(pod, ns).
+				WithStatusSubresource(pod).
+				Build()
+			exec := newBlockingExecutor()
+			close(exec.releaseCh)
+			recorder := events.NewFakeRecorder(100)
+			reconciler := &PodReconciler{
+				Client:                fakeClient,
+				Scheme:                scheme,
+				WarmupExecutor:        exec,
+				Recorder:              recorder,
+				BestEffortMissingGate: tt.bestEffort,
+			}
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "no-gate", Namespace: "default"}}
+
+			for range 2 {
+				if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+					t.Fatalf("Reconcile() error = %v", err)
+				}
+			}
+
+			if ips, _ := exec.calls(); len(ips) != tt.wantCalls {
+				t.Errorf("executor called %d times, want %d", len(ips), tt.wantCalls)
+			}
+			var missing, bestEffort int
+			for len(recorder.Events) > 0 {
+				ev := <-recorder.Events
+				if strings.HasPrefix(ev, "Warning "+ReasonWarmupGateMissing) {
+					missing++
+				}
+				if strings.Contains(ev, "Best-effort warmup") {
+					bestEffort++
+				}
+			}
+			if missing != 1 {
+				t.Errorf("%s events = %d, want 1", ReasonWarmupGateMissing, missing)
+			}
+			if bestEffort != tt.wantCalls {
+				t.Errorf("best-effort result events = %d, want %d", bestEffort, tt.wantCalls)
+			}
+			if got := testutil.ToFloat64(metrics.WarmupGateMissingTotal.WithLabelValues("default")); got != 1 {
+				t.Errorf("warmup_gate_missing_total = %v, want 1", got)
+			}
+
+			updated := &corev1.Pod{}
+			if err := reconciler.Get(context.Background(), req.NamespacedName, updated); err != nil {
+				t.Fatalf("failed to get pod: %v", err)
+			}
+			for _, condition := range updated.Status.Conditions {
+				if string(condition.Type) == webhook.ConditionTypeWarmupReady {
+					t.Error("warmup condition set on a pod without the readiness gate")
+				}
+			}
+		})
+	}
+}
+
+func TestMissingGateTracker_RecreatedPod(t *testing.T) {
+	var tracker missingGateTracker
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-0", Namespace: "default", UID: "uid-1"}}
+
+	if !tracker.report(pod) {
+		t.Error("report() for a new pod = false, want true")
+	}
+	if tracker.report(pod) {
+		t.Error("report() for a known pod = true, want false")
+	}
+	if !tracker.startWarmup(pod) || tracker.startWarmup(pod) {
+		t.Error("startWarmup() should succeed exactly once per pod")
+	}
+
+	// A StatefulSet pod recreated under the same name is a new pod.
+	recreated := pod.DeepCopy()
+	recreated.UID = "uid-2"
+	if !tracker.report(recreated) {
+		t.Error("report() for a recreated pod = false, want true")
+	}
+
+	tracker.forget(types.NamespacedName{Name: "web-0", Namespace: "default"})
+	if !tracker.report(recreated) {
+		t.Error("report() after forget() = false, want true")
+	}
+}
+
+func TestWarmupPodPredicate(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a",
+			Labels: map[string]string{webhook.AnnotationWarmupEnabled: webhook.WarmupEnabledValue},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&v1alpha1.WarmupPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-b"},
+			Spec:       v1alpha1.WarmupPolicySpec{PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}},
+		},
+	).Build()
+	r := &PodReconciler{Client: c}
+	p := WarmupPodPredicate(func(pod *corev1.Pod) bool {
+		return r.warmupEnabled(context.Background(), pod)
+	})
+
+	tests := []struct {
+		name        string
+		namespace   string
+		labels      map[string]string
+		annotations map[string]string
+		gate        bool
+		want        bool
+	}{
+		{name: "readiness gate", namespace: "team-b", gate: true, want: true},
+		{name: "annotation without gate", namespace: "team-b", annotations: map[string]string{webhook.AnnotationWarmupEnabled: webhook.WarmupEnabledValue}, want: true},
+		{name: "annotation disabled", namespace: "team-a", annotations: map[string]string{webhook.AnnotationWarmupEnabled: "disabled"}},
+		{name: "enabled by namespace", namespace: "team-a", want: true},
+		{name: "enabled by policy", namespace: "team-b", labels: map[string]string{"app": "web"}, want: true},
+		{name: "unrelated pod", namespace: "team-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "p", Namespace: tt.namespace, Labels: tt.labels, Annotations: tt.annotations}}
+			if tt.gate {
+				pod.Spec.ReadinessGates = []corev1.PodReadinessGate{
+					{ConditionType: corev1.PodConditionType(webhook.ReadinessGateName)},
+				}
+			}
+			if got := p.Create(event.CreateEvent{Object: pod}); got != tt.want {
+				t.Errorf("WarmupPodPredicate().Create() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
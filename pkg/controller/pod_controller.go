@@ -3,9 +3,9 @@ package controller
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
-	"golang.org/x/sync/semaphore"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,11 +24,16 @@ import (
 
 // Event reason constants
 const (
-	ReasonWarmupQueued     = "WarmupQueued"
-	ReasonWarmupStarted    = "WarmupStarted"
-	ReasonWarmupCompleted  = "WarmupCompleted"
-	ReasonWarmupFailed     = "WarmupFailed"
-	ReasonConditionUpdated = "ConditionUpdated"
+	ReasonWarmupQueued         = "WarmupQueued"
+	ReasonWarmupStarted        = "WarmupStarted"
+	ReasonWarmupCompleted      = "WarmupCompleted"
+	ReasonWarmupFailed         = "WarmupFailed"
+	ReasonWarmupCancelled      = "WarmupCancelled"
+	ReasonWarmupHandedOff      = "WarmupHandedOff"
+	ReasonWarmupGateMissing    = "WarmupGateMissing"
+	ReasonWarmupDefaults       = "WarmupDefaultsApplied"
+	ReasonWarmupConfigResolved = "WarmupConfigResolved"
+	ReasonConditionUpdated     = "ConditionUpdated"
 )
 
 // PodReconciler reconciles pods with warmup readiness gates
@@ -38,7 +43,44 @@ type PodReconciler struct {
 	WarmupExecutor   warmup.Executor
 	ScenarioExecutor warmup.ScenarioExecutor // nil = CRD-based warmup disabled
 	Recorder         events.EventRecorder
-	WarmupSemaphore  *semaphore.Weighted // nil = unlimited concurrency
+	WarmupScheduler  *FairScheduler // nil = unlimited concurrency
+	WarmupPool       *WarmupPool    // nil = warmups run inline in the reconcile worker (not cancellable)
+
+	// BestEffortMissingGate warms up pods that request warmup but were created
+	// without the readiness gate. Their readiness is not held back.
+	BestEffortMissingGate bool
+
+	// RecordWarmupRuns creates a WarmupRun for each finished warmup. The run is
+	// owned by the pod, unless WarmupRunRetention is positive: it is then kept for
+	// that long after the warmup, even if the pod is deleted.
+	RecordWarmupRuns   bool
+	WarmupRunRetention time.Duration
+
+	// Workloads resolves the workload that owns each pod for metric labels. When
+	// nil, the workload is guessed from the owner's name without API calls.
+	Workloads *WorkloadResolver
+
+	// WorkloadMetricLabels sets the workload_kind and workload_name labels of the
+	// warmup outcome metrics. The effectiveness histograms always have them.
+	WorkloadMetricLabels bool
+
+	missingGates missingGateTracker
+}
+
+// warmupOutcome is a finished warmup run that has yet to be applied to the pod.
+type warmupOutcome struct {
+	result *warmup.Result
+	// configError is set when the warmup config could not be parsed. The failure
+	// event has already been emitted and no metrics were recorded.
+	configError bool
+	// summary is recorded on the pod for the WarmupConfig status when the warmup
+	// used a WarmupConfig.
+	summary *warmup.RunSummary
+	// run is created when RecordWarmupRuns is set.
+	run *v1alpha1.WarmupRun
+	// progressRecorded is set when the warmup recorded completed steps in the pod's
+	// progress annotation, which changed the pod after it was fetched.
+	progressRecorded bool
 }
 
 // Reconcile handles pod reconciliation
@@ -49,13 +91,29 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	pod := &corev1.Pod{}
 	if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
 		if errors.IsNotFound(err) {
-			// Pod was deleted
+			// Pod was deleted; stop any warmup still running for it
+			r.missingGates.forget(req.NamespacedName)
+			if r.WarmupPool != nil {
+				if cancelled := r.WarmupPool.CancelPod(req.NamespacedName); cancelled != nil {
+					r.recordCancelled(ctx, cancelled, "pod deleted")
+				}
+			}
 			return ctrl.Result{}, nil
 		}
 		logger.Error(err, "unable to fetch Pod")
 		return ctrl.Result{}, err
 	}
 
+	// Pods that are being deleted will never serve traffic
+	if pod.DeletionTimestamp != nil {
+		if r.WarmupPool != nil {
+			if cancelled := r.WarmupPool.Cancel(pod.UID); cancelled != nil {
+				r.recordCancelled(ctx, pod, "pod terminating")
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// Check if our readiness gate exists
 	hasGate := false
 	for _, gate := range pod.Spec.ReadinessGates {
@@ -66,6 +124,10 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	if !hasGate {
+		if r.warmupEnabled(ctx, pod) {
+			// The webhook did not inject the gate (failurePolicy: Ignore)
+			return r.reconcileMissingGate(ctx, pod)
+		}
 		// This shouldn't happen due to predicates, but safety check
 		return ctrl.Result{}, nil
 	}
@@ -73,6 +135,15 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	// Check if our condition is already True
 	if r.isConditionTrue(pod, webhook.ConditionTypeWarmupReady) {
 		logger.V(1).Info("warmup condition already True, skipping")
+		if r.WarmupPool != nil {
+			// The watchdog may have set the condition before the outcome of a finished
+			// warmup was applied; there is nothing left to apply it to
+			if job := r.WarmupPool.Get(pod.UID); job != nil {
+				if _, done := job.finished(); done {
+					r.WarmupPool.Forget(pod.UID, job)
+				}
+			}
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -95,55 +166,146 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	}
 
 	// All conditions met, execute warmup
+	if r.WarmupPool != nil {
+		return r.reconcileInPool(ctx, pod)
+	}
+
+	outcome := r.runWarmup(ctx, pod)
+	if outcome == nil {
+		// Context cancelled while waiting for a concurrency slot
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if outcome.progressRecorded {
+		// The condition is updated against the pod's current version
+		if err := r.Get(ctx, req.NamespacedName, pod); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	if err := r.finishWarmup(ctx, pod, outcome); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
 
-	// Acquire semaphore if concurrency limiting is enabled
-	if r.WarmupSemaphore != nil {
+// reconcileInPool starts the pod's warmup in the WarmupPool, or applies its outcome
+// once the pool reports that it has finished.
+func (r *PodReconciler) reconcileInPool(ctx context.Context, pod *corev1.Pod) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	job := r.WarmupPool.Get(pod.UID)
+	if job != nil && job.podIP != pod.Status.PodIP {
+		// The warmup was aimed at an address the pod no longer has
+		if r.WarmupPool.Cancel(pod.UID) != nil {
+			r.recordCancelled(ctx, pod, fmt.Sprintf("pod IP changed from %s to %s, restarting", job.podIP, pod.Status.PodIP))
+		}
+		job = nil
+	}
+
+	if job == nil {
+		if !r.WarmupPool.Submit(ctx, pod, func(jobCtx context.Context) *warmupOutcome {
+			return r.runWarmup(jobCtx, pod)
+		}) {
+			logger.Info("controller shutting down, leaving warmup to the next instance")
+		}
+		return ctrl.Result{}, nil
+	}
+
+	outcome, done := job.finished()
+	if !done {
+		logger.V(1).Info("warmup already in progress")
+		return ctrl.Result{}, nil
+	}
+	if outcome == nil {
+		// The job ended before it got a concurrency slot; try again later
+		r.WarmupPool.Forget(pod.UID, job)
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	if err := r.finishWarmup(ctx, pod, outcome); err != nil {
+		// Keep the outcome so the retry applies it without warming up again
+		return ctrl.Result{}, err
+	}
+	r.WarmupPool.Forget(pod.UID, job)
+	return ctrl.Result{}, nil
+}
+
+// runWarmup waits for a concurrency slot and executes the warmup for pod. It returns
+// nil if ctx ends before a slot is granted.
+func (r *PodReconciler) runWarmup(ctx context.Context, pod *corev1.Pod) *warmupOutcome {
+	logger := log.FromContext(ctx)
+
+	// Acquire a concurrency slot if concurrency limiting is enabled
+	if r.WarmupScheduler != nil {
 		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupQueued, "QueueWarmup",
 			"Pod queued for warmup execution (waiting for concurrency slot)")
 		waitStart := time.Now()
-		if err := r.WarmupSemaphore.Acquire(ctx, 1); err != nil {
+		if err := r.WarmupScheduler.Acquire(ctx, pod.Namespace, podPriority(pod)); err != nil {
 			// Context cancelled while waiting; record partial wait before requeuing
 			metrics.RecordWarmupQueueWait(pod.Namespace, time.Since(waitStart).Seconds())
-			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			return nil
 		}
-		defer r.WarmupSemaphore.Release(1)
+		defer r.WarmupScheduler.Release()
 		metrics.RecordWarmupQueueWait(pod.Namespace, time.Since(waitStart).Seconds())
 	}
-
-	logger.Info("starting warmup execution", "pod", pod.Name, "namespace", pod.Namespace)
-	r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupStarted, "StartWarmup", "Starting warmup execution")
+	markWarmupStarted(ctx)
+	start := time.Now()
+
+	progress := r.warmupProgress(ctx, pod)
+	attempt := progress.NextAttempt()
+	logger.Info("starting warmup execution", "pod", pod.Name, "namespace", pod.Namespace, "attempt", attempt)
+	if attempt > 1 {
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupStarted, "StartWarmup",
+			"Starting warmup execution (attempt %d)", attempt)
+	} else {
+		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupStarted, "StartWarmup", "Starting warmup execution")
+	}
 
 	// Increment pending warmup gauge and ensure it's decremented when function returns
 	metrics.IncrementWarmupActivePods(pod.Namespace, pod.Spec.NodeName)
 	defer metrics.DecrementWarmupActivePods(pod.Namespace, pod.Spec.NodeName)
 
-	// Parse warmup configuration from pod annotations
-	config, err := warmup.ParseConfig(pod)
+	// Parse warmup configuration from pod annotations, with policy and namespace defaults
+	defaults := r.warmupDefaults(ctx, pod)
+	config, err := warmup.ParseConfigWithDefaults(pod, defaults)
 	if err != nil {
 		// Config parsing failed (likely port determination issue)
 		// Log error and mark as failed-open
 		logger.Error(err, "failed to parse warmup config")
 		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupFailed, "FailWarmup",
 			"Warmup config error: %v", err)
-		result := &warmup.Result{
-			Success: false,
-			Message: fmt.Sprintf("warmup config error: %v", err),
-			Error:   err,
+		outcome := &warmupOutcome{
+			result: &warmup.Result{
+				Success: false,
+				Message: fmt.Sprintf("warmup config error: %v", err),
+				Error:   err,
+			},
+			configError: true,
 		}
-		if setErr := r.setConditionTrue(ctx, pod, result); setErr != nil {
-			logger.Error(setErr, "failed to update pod condition")
-			return ctrl.Result{}, setErr
+		if r.RecordWarmupRuns {
+			outcome.run = newWarmupRun(pod, attempt, nil, outcome.result, start, time.Now(), r.WarmupRunRetention)
 		}
-		logger.Info("warmup skipped due to config error (fail-open)", "error", err)
-		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonConditionUpdated, "UpdateCondition",
-			"Pod condition %s set to True (fail-open)", webhook.ConditionTypeWarmupReady)
-		return ctrl.Result{}, nil
+		return outcome
 	}
 
+	r.recordSettingSources(pod, defaults, config)
+
 	// Set pod information
 	config.PodIP = pod.Status.PodIP
 	config.PodName = pod.Name
 	config.PodNamespace = pod.Namespace
+	config.Progress = progress
+	var progressRecorded bool
+	if hasReadinessGate(pod) {
+		// Record steps as they complete, so that a controller that stops without
+		// handing off, e.g. because it was killed, is resumed from them as well
+		config.OnProgress = func(p *warmup.Progress) {
+			p.Attempt = attempt
+			if err := r.recordProgress(ctx, pod, p); err != nil {
+				logger.Error(err, "failed to record warmup progress")
+				return
+			}
+			progressRecorded = true
+		}
+	}
 
 	// Execute warmup with a context timeout that includes a 5s grace period beyond
 	// the configured warmup timeout. This ensures the reconcile goroutine doesn't
@@ -156,24 +318,32 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 	var result *warmup.Result
 	recordMetrics := true
 	if config.WarmupConfigName != "" && r.ScenarioExecutor != nil {
-		warmupCfg := &v1alpha1.WarmupConfig{}
-		if err := r.Get(warmupCtx, types.NamespacedName{
-			Name:      config.WarmupConfigName,
-			Namespace: pod.Namespace,
-		}, warmupCfg); err != nil {
-			// WarmupConfig not found: emit a warning and fail the warmup (fail-open means pod
-			// is still marked READY by the condition update below, consistent with other failures).
-			logger.Error(err, "WarmupConfig not found, failing open",
-				"warmupConfig", config.WarmupConfigName)
-			r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupFailed, "LookupWarmupConfig",
-				"WarmupConfig %q not found: %v", config.WarmupConfigName, err)
+		spec, resolved, err := r.lookupWarmupConfig(warmupCtx, pod, config, defaults.Namespace)
+		if err == nil && resolved != nil {
+			// Record the expanded step list so that composed scenarios can be debugged
+			logger.V(1).Info("expanded warmup config", "warmupConfig", config.WarmupConfigRef(), "steps", resolved)
+			r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupConfigResolved, "ResolveWarmupConfig",
+				"Resolved %s to %d steps: %s", config.WarmupConfigRef(), len(resolved), strings.Join(resolved, ", "))
+		}
+		if err == nil {
+			err = resolveWarmupParams(config, spec)
+		}
+		if err != nil {
+			// Config unavailable (missing, not allowed, an include cycle, or missing
+			// required parameters):
+			// emit a warning and fail the warmup
+			// (fail-open means pod is still marked READY by the condition update below,
+			// consistent with other failures).
+			logger.Error(err, "warmup config unavailable, failing open",
+				"warmupConfig", config.WarmupConfigRef())
+			r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupFailed, "LookupWarmupConfig", "%v", err)
 			result = &warmup.Result{
 				Success: false,
 				Error:   err,
-				Message: fmt.Sprintf("WarmupConfig %q not found", config.WarmupConfigName),
+				Message: err.Error(),
 			}
 		} else {
-			result = r.ScenarioExecutor.ExecuteScenario(warmupCtx, config, &warmupCfg.Spec)
+			result = r.ScenarioExecutor.ExecuteScenario(warmupCtx, config, spec)
 		}
 	} else if r.WarmupExecutor != nil {
 		result = r.WarmupExecutor.Execute(warmupCtx, config)
@@ -186,28 +356,77 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		logger.Info("warmup skipped: no executor configured")
 	}
 
-	// Record warmup metrics (skip when no executor, as no actual warmup was performed)
-	if recordMetrics {
-		metrics.RecordWarmupResult(pod.Namespace, result.Success, result.TotalDuration.Seconds())
-		metrics.RecordWarmupRequests(pod.Namespace, result.RequestsCompleted+result.RequestsFailed)
+	// Record warmup metrics (skip when no executor, as no actual warmup was performed).
+	// A warmup whose job was cancelled is counted separately from failures.
+	var workload metrics.Workload
+	if recordMetrics && r.WorkloadMetricLabels {
+		workload = r.metricWorkload(ctx, pod)
+	}
+	if recordMetrics && ctx.Err() != nil {
+		metrics.RecordWarmupCancelled(pod.Namespace, workload)
+		metrics.RecordWarmupRequests(pod.Namespace, workload, result.Outcomes)
+	} else if recordMetrics {
+		metrics.RecordWarmupResult(pod.Namespace, workload, result.Success, result.TotalDuration.Seconds())
+		metrics.RecordWarmupRequests(pod.Namespace, workload, result.Outcomes)
+		for _, step := range result.Steps {
+			if step.Failed() {
+				metrics.RecordWarmupStepFailure(pod.Namespace, workload, config.WarmupConfigRef(), step.Name)
+			}
+		}
+		if result.SpeedupRatio > 0 {
+			metrics.RecordWarmupEffectiveness(pod.Namespace, workload, result.FirstRequestLatency.Seconds(),
+				result.FinalWindowLatency.Seconds(), result.SpeedupRatio)
+		}
+	}
+
+	outcome := &warmupOutcome{result: result, progressRecorded: progressRecorded}
+	if config.WarmupConfigName != "" && r.ScenarioExecutor != nil && ctx.Err() == nil {
+		outcome.summary = warmup.NewRunSummary(config, result, time.Now())
 	}
+	if r.RecordWarmupRuns && ctx.Err() == nil {
+		outcome.run = newWarmupRun(pod, attempt, config, result, start, time.Now(), r.WarmupRunRetention)
+	}
+	return outcome
+}
+
+// finishWarmup emits the result events for outcome and sets the warmup condition
+// to True (fail-open: always True even if warmup fails).
+func (r *PodReconciler) finishWarmup(ctx context.Context, pod *corev1.Pod, outcome *warmupOutcome) error {
+	logger := log.FromContext(ctx)
+	result := outcome.result
 
 	// Log and emit events for warmup result first
-	if result.Success {
-		logger.Info("warmup completed successfully", "message", result.Message)
-		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupCompleted, "CompleteWarmup", "%s", result.Message)
-	} else {
-		logger.Info("warmup failed", "message", result.Message, "error", result.Error)
-		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupFailed, "FailWarmup",
-			"Warmup failed: %s", result.Message)
+	if !outcome.configError {
+		for _, req := range result.FailedRequests() {
+			logger.V(1).Info("warmup request failed", "request", req.Name, "requestsFailed", req.RequestsFailed,
+				"statusCodes", req.StatusCodes, "errors", req.Errors)
+		}
+		if result.Success {
+			logger.Info("warmup completed successfully", "message", result.Message)
+			r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupCompleted, "CompleteWarmup", "%s", result.Message)
+		} else {
+			logger.Info("warmup failed", "message", result.Message, "error", result.Error)
+			r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupFailed, "FailWarmup",
+				"Warmup failed: %s", result.Message)
+		}
 	}
 
-	// Set condition to True (fail-open behavior: always True even if warmup fails)
 	if err := r.setConditionTrue(ctx, pod, result); err != nil {
 		logger.Error(err, "failed to update pod condition")
-		return ctrl.Result{}, err
+		return err
+	}
+	r.clearWarmupProgress(ctx, pod)
+	if outcome.summary != nil {
+		r.recordRunSummary(ctx, pod, outcome.summary)
+	}
+	if outcome.run != nil {
+		r.recordWarmupRun(ctx, outcome.run)
+	}
+	if outcome.configError {
+		logger.Info("warmup skipped due to config error (fail-open)", "error", result.Error)
+	} else {
+		logger.Info("pod condition updated to True", "failOpen", !result.Success)
 	}
-	logger.Info("pod condition updated to True", "failOpen", !result.Success)
 	if result.Success {
 		r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonConditionUpdated, "UpdateCondition",
 			"Pod condition %s set to True", webhook.ConditionTypeWarmupReady)
@@ -215,8 +434,193 @@ func (r *PodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.R
 		r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonConditionUpdated, "UpdateCondition",
 			"Pod condition %s set to True (fail-open)", webhook.ConditionTypeWarmupReady)
 	}
+	return nil
+}
 
-	return ctrl.Result{}, nil
+// recordCancelled logs and emits an event for a warmup that was cancelled for reason.
+func (r *PodReconciler) recordCancelled(ctx context.Context, pod *corev1.Pod, reason string) {
+	log.FromContext(ctx).Info("cancelled in-flight warmup", "reason", reason)
+	r.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupCancelled, "CancelWarmup",
+		"Warmup cancelled: %s", reason)
+}
+
+// handoffWarmup is called by the WarmupPool during shutdown for each job it could
+// not see through. A finished outcome is applied right away. An interrupted warmup
+// is recorded in the pod's progress annotation, so the next controller instance
+// resumes or restarts it with the correct attempt count.
+func (r *PodReconciler) handoffWarmup(ctx context.Context, job *warmupJob, interrupted bool) {
+	logger := log.FromContext(ctx).WithValues("pod", job.pod.Name, "namespace", job.pod.Namespace)
+
+	pod := &corev1.Pod{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(job.pod), pod); err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error(err, "unable to fetch Pod for warmup handoff")
+		}
+		return
+	}
+	if pod.UID != job.pod.UID || pod.DeletionTimestamp != nil || r.isConditionTrue(pod, webhook.ConditionTypeWarmupReady) {
+		return
+	}
+	if !hasReadinessGate(pod) {
+		// Best-effort warmup of a pod without the gate; nothing to hand off
+		return
+	}
+
+	if !interrupted {
+		if outcome, _ := job.finished(); outcome != nil {
+			if err := r.finishWarmup(ctx, pod, outcome); err != nil {
+				logger.Error(err, "failed to apply warmup outcome during shutdown")
+			}
+		}
+		return
+	}
+	if !job.started.Load() {
+		// Still waiting for a concurrency slot; this attempt never began
+		return
+	}
+
+	// Keep the scenario steps completed so far: those reported by the interrupted
+	// run, or those it recorded as they completed, or those of the earlier attempt
+	// if the run did not unwind in time
+	previous := r.warmupProgress(ctx, job.pod)
+	progress := &warmup.Progress{Attempt: previous.NextAttempt()}
+	if outcome, done := job.finished(); done && outcome != nil && outcome.result.Progress != nil {
+		progress.Steps = outcome.result.Progress.Steps
+		progress.Variables = outcome.result.Progress.Variables
+	} else if recorded := r.warmupProgress(ctx, pod); recorded != nil && recorded.Attempt == progress.Attempt {
+		progress.Steps = recorded.Steps
+		progress.Variables = recorded.Variables
+	} else if previous != nil {
+		progress.Steps = previous.Steps
+		progress.Variables = previous.Variables
+	}
+	if err := r.recordProgress(ctx, pod, progress); err != nil {
+		logger.Error(err, "failed to record warmup progress")
+		return
+	}
+	logger.Info("handed off interrupted warmup", "attempt", progress.Attempt, "completedSteps", len(progress.Steps))
+	r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupHandedOff, "HandOffWarmup",
+		"Controller shutting down; warmup attempt %d handed off to the next controller instance", progress.Attempt)
+}
+
+// recordProgress stores progress in the pod's progress annotation. pod is not
+// modified.
+func (r *PodReconciler) recordProgress(ctx context.Context, pod *corev1.Pod, progress *warmup.Progress) error {
+	value, err := progress.Annotation()
+	if err != nil {
+		return fmt.Errorf("encoding warmup progress: %w", err)
+	}
+	patched := pod.DeepCopy()
+	if patched.Annotations == nil {
+		patched.Annotations = map[string]string{}
+	}
+	patched.Annotations[webhook.AnnotationWarmupProgress] = value
+	return r.Patch(ctx, patched, client.MergeFrom(pod))
+}
+
+// clearWarmupProgress removes the progress recorded by this or earlier attempts
+// once the warmup is finished, so it is not resumed again. Failures are logged; the
+// annotation is not read after the condition is True.
+func (r *PodReconciler) clearWarmupProgress(ctx context.Context, pod *corev1.Pod) {
+	if _, ok := pod.Annotations[webhook.AnnotationWarmupProgress]; !ok {
+		return
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	delete(pod.Annotations, webhook.AnnotationWarmupProgress)
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		log.FromContext(ctx).Error(err, "failed to clear warmup progress")
+	}
+}
+
+// recordRunSummary stores summary in the pod's result annotation, from which the
+// WarmupConfig status is computed. Failures are logged; they only leave the run out
+// of the status.
+func (r *PodReconciler) recordRunSummary(ctx context.Context, pod *corev1.Pod, summary *warmup.RunSummary) {
+	logger := log.FromContext(ctx)
+	value, err := summary.Annotation()
+	if err != nil {
+		logger.Error(err, "failed to encode warmup result")
+		return
+	}
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[webhook.AnnotationWarmupResult] = value
+	if err := r.Patch(ctx, pod, patch); err != nil {
+		logger.Error(err, "failed to record warmup result")
+	}
+}
+
+// warmupProgress returns the progress handed off by a previous controller instance,
+// or nil if there is none. An unreadable annotation is logged and ignored.
+func (r *PodReconciler) warmupProgress(ctx context.Context, pod *corev1.Pod) *warmup.Progress {
+	progress, err := warmup.ParseProgress(pod)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "ignoring warmup progress annotation")
+	}
+	return progress
+}
+
+// warmupDefaults returns the policy and namespace the pod inherits warmup settings
+// from. Lookup failures are logged; the warmup then runs without that source.
+func (r *PodReconciler) warmupDefaults(ctx context.Context, pod *corev1.Pod) webhook.Defaults {
+	logger := log.FromContext(ctx)
+
+	var d webhook.Defaults
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: pod.Namespace}, ns); err == nil {
+		d.Namespace = ns
+	} else if !errors.IsNotFound(err) {
+		logger.Error(err, "failed to get namespace, ignoring namespace warmup settings")
+	}
+	policy, err := webhook.ResolvePolicy(ctx, r.Client, pod, pod.Namespace, d.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to resolve warmup policy, ignoring warmup policies")
+	}
+	d.Policy = policy
+	return d
+}
+
+// warmupEnabled reports whether warmup is enabled for pod, by its own annotation or
+// by the policy and namespace defaults the warmup itself would use. The defaults are
+// only looked up when the pod does not decide for itself.
+func (r *PodReconciler) warmupEnabled(ctx context.Context, pod *corev1.Pod) bool {
+	if value, ok := pod.Annotations[webhook.AnnotationWarmupEnabled]; ok {
+		return value == webhook.WarmupEnabledValue
+	}
+	enabled, _ := r.warmupDefaults(ctx, pod).WarmupEnabled(pod)
+	return enabled
+}
+
+// recordSettingSources emits an event listing where each warmup setting came from
+// when a policy or the namespace supplied any of them. Pods configured entirely by
+// their own annotations get no extra event.
+func (r *PodReconciler) recordSettingSources(pod *corev1.Pod, defaults webhook.Defaults, config *warmup.Config) {
+	_, enabledBy := defaults.WarmupEnabled(pod)
+	inherited := enabledBy != "" && enabledBy != webhook.SettingSourcePod
+	for _, source := range config.Sources {
+		if source != webhook.SettingSourcePod {
+			inherited = true
+		}
+	}
+	if !inherited {
+		return
+	}
+	if enabledBy == "" {
+		// The gate was injected, but nothing enables warmup for the pod any more
+		enabledBy = "readiness gate"
+	}
+	r.Recorder.Eventf(pod, nil, corev1.EventTypeNormal, ReasonWarmupDefaults, "ResolveWarmupSettings",
+		"Effective warmup settings: warmup=enabled (%s), %s", enabledBy, config.DescribeSources())
+}
+
+// podPriority returns the pod's resolved PriorityClass value, or 0 if none is set
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority != nil {
+		return *pod.Spec.Priority
+	}
+	return 0
 }
 
 // isConditionTrue checks if a pod condition is True
@@ -241,9 +645,6 @@ func (r *PodReconciler) areContainersReady(pod *corev1.Pod) bool {
 
 // setConditionTrue updates the pod condition to True
 func (r *PodReconciler) setConditionTrue(ctx context.Context, pod *corev1.Pod, result *warmup.Result) error {
-	// Create a copy for update
-	podCopy := pod.DeepCopy()
-
 	// Determine reason and message based on warmup result
 	reason := "WarmupComplete"
 	message := "Warmup readiness check passed"
@@ -255,6 +656,14 @@ func (r *PodReconciler) setConditionTrue(ctx context.Context, pod *corev1.Pod, r
 			message = "Warmup failed but pod marked ready (fail-open): " + result.Message
 		}
 	}
+	return setWarmupConditionTrue(ctx, r.Client, pod, reason, message)
+}
+
+// setWarmupConditionTrue sets the pod's warmup readiness condition to True with the
+// given reason and message
+func setWarmupConditionTrue(ctx context.Context, c client.Client, pod *corev1.Pod, reason, message string) error {
+	// Create a copy for update
+	podCopy := pod.DeepCopy()
 
 	// Find and update or add the condition
 	conditionUpdated := false
@@ -282,7 +691,7 @@ func (r *PodReconciler) setConditionTrue(ctx context.Context, pod *corev1.Pod, r
 	}
 
 	// Update pod status
-	if err := r.Status().Update(ctx, podCopy); err != nil {
+	if err := c.Status().Update(ctx, podCopy); err != nil {
 		return err
 	}
 
@@ -291,8 +700,19 @@ func (r *PodReconciler) setConditionTrue(ctx context.Context, pod *corev1.Pod, r
 
 // SetupWithManager sets up the controller with the Manager
 func (r *PodReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&corev1.Pod{}).
-		WithEventFilter(HasReadinessGatePredicate()).
-		Complete(r)
+		WithEventFilter(WarmupPodPredicate(func(pod *corev1.Pod) bool {
+			return r.warmupEnabled(context.Background(), pod)
+		}))
+	if r.WarmupPool != nil {
+		// Re-reconcile pods whose background warmup has finished
+		b = b.WatchesRawSource(r.WarmupPool.Source())
+		// Drain the pool when the manager shuts down
+		r.WarmupPool.handoff = r.handoffWarmup
+		if err := mgr.Add(r.WarmupPool); err != nil {
+			return err
+		}
+	}
+	return b.Complete(r)
 }
@@ -7,16 +7,19 @@ import (
 	"testing"
 	"time"
 
-	"golang.org/x/sync/semaphore"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/events"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
 	"github.com/hhiroshell/kube-booster/pkg/warmup"
 	"github.com/hhiroshell/kube-booster/pkg/webhook"
 )
@@ -814,7 +817,7 @@ func (e *slowMockExecutor) Execute(ctx context.Context, config *warmup.Config) *
 	return &warmup.Result{Success: true, RequestsCompleted: 1, Message: "slow mock"}
 }
 
-func makePodForSemaphoreTest(name string) *corev1.Pod {
+func makePodForSchedulerTest(name string) *corev1.Pod {
 	return &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -845,12 +848,12 @@ func makePodForSemaphoreTest(name string) *corev1.Pod {
 	}
 }
 
-func TestPodReconciler_Semaphore_SerializesExecution(t *testing.T) {
+func TestPodReconciler_Scheduler_SerializesExecution(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme) //nolint:errcheck
 
-	pod1 := makePodForSemaphoreTest("pod-1")
-	pod2 := makePodForSemaphoreTest("pod-2")
+	pod1 := makePodForSchedulerTest("pod-1")
+	pod2 := makePodForSchedulerTest("pod-2")
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
@@ -865,7 +868,7 @@ func TestPodReconciler_Semaphore_SerializesExecution(t *testing.T) {
 		Scheme:          scheme,
 		WarmupExecutor:  slowExec,
 		Recorder:        events.NewFakeRecorder(100),
-		WarmupSemaphore: semaphore.NewWeighted(1),
+		WarmupScheduler: NewFairScheduler(1),
 	}
 
 	var wg sync.WaitGroup
@@ -880,14 +883,14 @@ func TestPodReconciler_Semaphore_SerializesExecution(t *testing.T) {
 		})
 	}()
 
-	// Wait until the first reconcile has acquired the semaphore and started executing
+	// Wait until the first reconcile has acquired the slot and started executing
 	select {
 	case <-slowExec.startedCh:
 	case <-time.After(5 * time.Second):
 		t.Fatal("timed out waiting for first reconcile to start")
 	}
 
-	// Start second reconcile concurrently; it should queue behind the semaphore
+	// Start second reconcile concurrently; it should queue behind the first
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -910,11 +913,11 @@ func TestPodReconciler_Semaphore_SerializesExecution(t *testing.T) {
 	}
 }
 
-func TestPodReconciler_Semaphore_ContextCancelled(t *testing.T) {
+func TestPodReconciler_Scheduler_ContextCancelled(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme) //nolint:errcheck
 
-	pod := makePodForSemaphoreTest("pod-1")
+	pod := makePodForSchedulerTest("pod-1")
 
 	fakeClient := fake.NewClientBuilder().
 		WithScheme(scheme).
@@ -922,10 +925,10 @@ func TestPodReconciler_Semaphore_ContextCancelled(t *testing.T) {
 		WithStatusSubresource(pod).
 		Build()
 
-	// Fully acquire the semaphore externally so Reconcile has to wait
-	sem := semaphore.NewWeighted(1)
-	if err := sem.Acquire(context.Background(), 1); err != nil {
-		t.Fatalf("failed to pre-acquire semaphore: %v", err)
+	// Fully acquire the scheduler externally so Reconcile has to wait
+	sched := NewFairScheduler(1)
+	if err := sched.Acquire(context.Background(), "other", 0); err != nil {
+		t.Fatalf("failed to pre-acquire scheduler slot: %v", err)
 	}
 
 	reconciler := &PodReconciler{
@@ -933,7 +936,7 @@ func TestPodReconciler_Semaphore_ContextCancelled(t *testing.T) {
 		Scheme:          scheme,
 		WarmupExecutor:  &warmup.MockExecutor{Result: &warmup.Result{Success: true}},
 		Recorder:        events.NewFakeRecorder(100),
-		WarmupSemaphore: sem,
+		WarmupScheduler: sched,
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -1141,3 +1144,696 @@ func TestPodReconciler_EventRecording(t *testing.T) {
 		})
 	}
 }
+
+func TestPodReconciler_NamespaceDefaults(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a",
+			Labels: map[string]string{webhook.AnnotationWarmupEnabled: webhook.WarmupEnabledValue},
+			Annotations: map[string]string{
+				webhook.AnnotationWarmupEndpoint: "/warm",
+				webhook.AnnotationWarmupRequests: "10",
+			},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "team-a",
+			Annotations: map[string]string{
+				webhook.AnnotationWarmupRequests: "20",
+				webhook.AnnotationWarmupPort:     "8080",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+			ReadinessGates: []corev1.PodReadinessGate{
+				{ConditionType: corev1.PodConditionType(webhook.ReadinessGateName)},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(ns, pod).
+		WithStatusSubresource(pod).
+		Build()
+	fakeRecorder := events.NewFakeRecorder(100)
+	executor := &configCapturingExecutor{}
+	reconciler := &PodReconciler{
+		Client:         client,
+		Scheme:         scheme,
+		WarmupExecutor: executor,
+		Recorder:       fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if executor.config == nil {
+		t.Fatal("executor was not called")
+	}
+	if executor.config.Endpoint != "/warm" || executor.config.RequestCount != 20 {
+		t.Errorf("config endpoint = %q, requests = %d, want /warm from namespace and 20 from pod",
+			executor.config.Endpoint, executor.config.RequestCount)
+	}
+
+	close(fakeRecorder.Events)
+	var sourcesEvent string
+	for event := range fakeRecorder.Events {
+		if strings.Contains(event, ReasonWarmupDefaults) {
+			sourcesEvent = event
+		}
+	}
+	for _, want := range []string{
+		"warmup=enabled (namespace)",
+		"warmup-endpoint=/warm (namespace)",
+		"warmup-requests=20 (pod annotation)",
+		"warmup-timeout=30s (default)",
+	} {
+		if !strings.Contains(sourcesEvent, want) {
+			t.Errorf("%s event = %q, want it to contain %q", ReasonWarmupDefaults, sourcesEvent, want)
+		}
+	}
+}
+
+func TestPodReconciler_WarmupPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	policy := &v1alpha1.WarmupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"},
+		Spec: v1alpha1.WarmupPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Priority:    10,
+			Port:        8080,
+			Settings:    &v1alpha1.WarmupPolicySettings{Endpoint: "/policy", Requests: 7},
+		},
+	}
+	clusterPolicy := &v1alpha1.ClusterWarmupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "all"},
+		Spec: v1alpha1.WarmupPolicySpec{
+			Port:     9090,
+			Settings: &v1alpha1.WarmupPolicySettings{Endpoint: "/cluster"},
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-pod",
+			Namespace: "team-a",
+			Labels:    map[string]string{"app": "web"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+			ReadinessGates: []corev1.PodReadinessGate{
+				{ConditionType: corev1.PodConditionType(webhook.ReadinessGateName)},
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			PodIP: "10.0.0.1",
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+		},
+	}
+
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(policy, clusterPolicy, pod).
+		WithStatusSubresource(pod).
+		Build()
+	fakeRecorder := events.NewFakeRecorder(100)
+	executor := &configCapturingExecutor{}
+	reconciler := &PodReconciler{
+		Client:         client,
+		Scheme:         scheme,
+		WarmupExecutor: executor,
+		Recorder:       fakeRecorder,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}}
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if executor.config == nil {
+		t.Fatal("executor was not called")
+	}
+	if executor.config.Endpoint != "/policy" || executor.config.RequestCount != 7 || executor.config.Port != 8080 {
+		t.Errorf("config endpoint = %q, requests = %d, port = %d, want the higher-priority WarmupPolicy's /policy, 7, 8080",
+			executor.config.Endpoint, executor.config.RequestCount, executor.config.Port)
+	}
+
+	close(fakeRecorder.Events)
+	var sourcesEvent string
+	for event := range fakeRecorder.Events {
+		if strings.Contains(event, ReasonWarmupDefaults) {
+			sourcesEvent = event
+		}
+	}
+	for _, want := range []string{
+		"warmup=enabled (WarmupPolicy team-a/web)",
+		"warmup-endpoint=/policy (WarmupPolicy team-a/web)",
+		"warmup-port=8080 (WarmupPolicy team-a/web)",
+	} {
+		if !strings.Contains(sourcesEvent, want) {
+			t.Errorf("%s event = %q, want it to contain %q", ReasonWarmupDefaults, sourcesEvent, want)
+		}
+	}
+}
+
+// configCapturingExecutor records the config it was called with.
+type configCapturingExecutor struct {
+	config *warmup.Config
+}
+
+func (e *configCapturingExecutor) Execute(_ context.Context, config *warmup.Config) *warmup.Result {
+	e.config = config
+	return &warmup.Result{Success: true, RequestsCompleted: config.RequestCount}
+}
+
+// blockingExecutor records every Execute call and blocks until released or cancelled.
+type blockingExecutor struct {
+	mu        sync.Mutex
+	ips       []string
+	cancelled int
+	startedCh chan struct{} // receives once per Execute call
+	releaseCh chan struct{} // close to let running and future calls complete
+}
+
+func newBlockingExecutor() *blockingExecutor {
+	return &blockingExecutor{
+		startedCh: make(chan struct{}, 10),
+		releaseCh: make(chan struct{}),
+	}
+}
+
+func (e *blockingExecutor) Execute(ctx context.Context, config *warmup.Config) *warmup.Result {
+	e.mu.Lock()
+	e.ips = append(e.ips, config.PodIP)
+	e.mu.Unlock()
+	e.startedCh <- struct{}{}
+	select {
+	case <-e.releaseCh:
+		return &warmup.Result{Success: true, RequestsCompleted: 1, Message: "blocking mock"}
+	case <-ctx.Done():
+		e.mu.Lock()
+		e.cancelled++
+		e.mu.Unlock()
+		return &warmup.Result{Success: false, Error: ctx.Err(), Message: "cancelled"}
+	}
+}
+
+func (e *blockingExecutor) calls() ([]string, int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.ips...), e.cancelled
+}
+
+func waitForStart(t *testing.T, e *blockingExecutor) {
+	t.Helper()
+	select {
+	case <-e.startedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for warmup to start")
+	}
+}
+
+func newPoolTestReconciler(t *testing.T, exec warmup.Executor, pod *corev1.Pod) (*PodReconciler, *WarmupPool) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pod).
+		WithStatusSubresource(pod).
+		Build()
+
+	pool := NewWarmupPool()
+	return &PodReconciler{
+		Client:         fakeClient,
+		Scheme:         scheme,
+		WarmupExecutor: exec,
+		Recorder:       events.NewFakeRecorder(100),
+		WarmupPool:     pool,
+	}, pool
+}
+
+func TestPodReconciler_Pool_DoesNotBlockReconcile(t *testing.T) {
+	pod := makePodForSchedulerTest("pod-1")
+	pod.UID = "uid-1"
+	exec := newBlockingExecutor()
+	reconciler, pool := newPoolTestReconciler(t, exec, pod)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-1", Namespace: "default"}}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := reconciler.Reconcile(context.Background(), req)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Reconcile() blocked while warmup is running")
+	}
+	waitForStart(t, exec)
+
+	// A second reconcile while the warmup runs must not start a duplicate.
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("second Reconcile() error = %v", err)
+	}
+	if ips, _ := exec.calls(); len(ips) != 1 {
+		t.Errorf("executor called %d times, want 1", len(ips))
+	}
+
+	// Completion is fed back as an event for the pod.
+	close(exec.releaseCh)
+	select {
+	case ev := <-pool.events:
+		if ev.Object.Name != "pod-1" {
+			t.Errorf("completion event for %q, want pod-1", ev.Object.Name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for completion event")
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() after completion error = %v", err)
+	}
+	updated := &corev1.Pod{}
+	if err := reconciler.Get(context.Background(), req.NamespacedName, updated); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	if !reconciler.isConditionTrue(updated, webhook.ConditionTypeWarmupReady) {
+		t.Error("warmup condition not True after completion was applied")
+	}
+	if n := pool.Len(); n != 0 {
+		t.Errorf("pool tracks %d jobs after completion, want 0", n)
+	}
+}
+
+func TestPodReconciler_Pool_ForgetsOutcomeAfterWatchdog(t *testing.T) {
+	pod := makePodForSchedulerTest("pod-1")
+	pod.UID = "uid-1"
+	exec := newBlockingExecutor()
+	reconciler, pool := newPoolTestReconciler(t, exec, pod)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-1", Namespace: "default"}}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	waitForStart(t, exec)
+	close(exec.releaseCh)
+	select {
+	case <-pool.events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for completion event")
+	}
+
+	// The watchdog sets the condition before the outcome is applied
+	current := &corev1.Pod{}
+	if err := reconciler.Get(context.Background(), req.NamespacedName, current); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	if err := setWarmupConditionTrue(context.Background(), reconciler.Client, current, "WatchdogTimeout", "timed out"); err != nil {
+		t.Fatalf("setWarmupConditionTrue() error = %v", err)
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() after watchdog error = %v", err)
+	}
+	if n := pool.Len(); n != 0 {
+		t.Errorf("pool tracks %d jobs after the watchdog set the condition, want 0", n)
+	}
+}
+
+func TestPodReconciler_Pool_RestartsOnIPChange(t *testing.T) {
+	pod := makePodForSchedulerTest("pod-1")
+	pod.UID = "uid-1"
+	exec := newBlockingExecutor()
+	reconciler, pool := newPoolTestReconciler(t, exec, pod)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-1", Namespace: "default"}}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	waitForStart(t, exec)
+
+	current := &corev1.Pod{}
+	if err := reconciler.Get(context.Background(), req.NamespacedName, current); err != nil {
+		t.Fatalf("failed to get pod: %v", err)
+	}
+	current.Status.PodIP = "10.0.0.2"
+	if err := reconciler.Status().Update(context.Background(), current); err != nil {
+		t.Fatalf("failed to update pod IP: %v", err)
+	}
+
+	if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() after IP change error = %v", err)
+	}
+	waitForStart(t, exec)
+
+	waitFor(t, func() bool {
+		_, cancelled := exec.calls()
+		return cancelled == 1
+	})
+	ips, _ := exec.calls()
+	if len(ips) != 2 || ips[0] != "10.0.0.1" || ips[1] != "10.0.0.2" {
+		t.Errorf("warmup targets = %v, want [10.0.0.1 10.0.0.2]", ips)
+	}
+	if job := pool.Get(pod.UID); job == nil || job.podIP != "10.0.0.2" {
+		t.Error("pool should track the restarted warmup for the new IP")
+	}
+	close(exec.releaseCh)
+}
+
+func TestPodReconciler_Pool_CancelsOnDelete(t *testing.T) {
+	tests := []struct {
+		name       string
+		finalizer  bool // keeps the pod around with a DeletionTimestamp
+		wantReason string
+	}{
+		{name: "pod deleted", wantReason: "pod deleted"},
+		{name: "pod terminating", finalizer: true, wantReason: "pod terminating"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics.WarmupTotal.Reset()
+
+			pod := makePodForSchedulerTest("pod-1")
+			pod.UID = "uid-1"
+			if tt.finalizer {
+				pod.Finalizers = []string{"example.com/hold"}
+			}
+			exec := newBlockingExecutor()
+			reconciler, pool := newPoolTestReconciler(t, exec, pod)
+			recorder := reconciler.Recorder.(*events.FakeRecorder)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-1", Namespace: "default"}}
+
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			waitForStart(t, exec)
+
+			if err := reconciler.Delete(context.Background(), pod); err != nil {
+				t.Fatalf("failed to delete pod: %v", err)
+			}
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() after delete error = %v", err)
+			}
+
+			waitFor(t, func() bool {
+				_, cancelled := exec.calls()
+				return cancelled == 1
+			})
+			if n := pool.Len(); n != 0 {
+				t.Errorf("pool tracks %d jobs after delete, want 0", n)
+			}
+			select {
+			case ev := <-pool.events:
+				t.Errorf("unexpected completion event for cancelled warmup of %q", ev.Object.Name)
+			case <-time.After(100 * time.Millisecond):
+			}
+
+			var sawCancelled bool
+			for len(recorder.Events) > 0 {
+				ev := <-recorder.Events
+				if strings.Contains(ev, ReasonWarmupCancelled) {
+					sawCancelled = true
+					if !strings.Contains(ev, tt.wantReason) {
+						t.Errorf("cancel event = %q, want reason %q", ev, tt.wantReason)
+					}
+				}
+			}
+			if !sawCancelled {
+				t.Errorf("no %s event recorded", ReasonWarmupCancelled)
+			}
+
+			waitFor(t, func() bool {
+				return testutil.ToFloat64(metrics.WarmupTotal.WithLabelValues("default", "", "", "cancelled")) == 1
+			})
+			if got := testutil.ToFloat64(metrics.WarmupTotal.WithLabelValues("default", "", "", "failure")); got != 0 {
+				t.Errorf("warmup_total{result=failure} = %v, want 0 for a cancelled warmup", got)
+			}
+		})
+	}
+}
+
+func TestPodReconciler_Pool_HandsOffOnShutdown(t *testing.T) {
+	tests := []struct {
+		name          string
+		progress      string // existing progress annotation
+		finish        bool   // warmup completes within the grace period
+		wantCondition bool
+		wantProgress  string
+		wantStarted   string
+	}{
+		{
+			name:          "finished within grace period",
+			finish:        true,
+			wantCondition: true,
+			wantStarted:   "Starting warmup execution",
+		},
+		{
+			name:          "resumed attempt finished clears progress",
+			progress:      `{"attempt":1,"steps":["login"]}`,
+			finish:        true,
+			wantCondition: true,
+			wantStarted:   "Starting warmup execution (attempt 2)",
+		},
+		{
+			name:         "interrupted",
+			wantProgress: `{"attempt":1}`,
+			wantStarted:  "Starting warmup execution",
+		},
+		{
+			name:         "interrupted again keeps completed steps",
+			progress:     `{"attempt":1,"steps":["login"]}`,
+			wantProgress: `{"attempt":2,"steps":["login"]}`,
+			wantStarted:  "Starting warmup execution (attempt 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makePodForSchedulerTest("pod-1")
+			pod.UID = "uid-1"
+			if tt.progress != "" {
+				pod.Annotations[webhook.AnnotationWarmupProgress] = tt.progress
+			}
+			exec := newBlockingExecutor()
+			reconciler, pool := newPoolTestReconciler(t, exec, pod)
+			pool.gracePeriod = 100 * time.Millisecond
+			if tt.finish {
+				pool.gracePeriod = 5 * time.Second
+			}
+			pool.handoff = reconciler.handoffWarmup
+			recorder := reconciler.Recorder.(*events.FakeRecorder)
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "pod-1", Namespace: "default"}}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			stopped := make(chan struct{})
+			go func() {
+				_ = pool.Start(ctx) //nolint:errcheck // Start never fails
+				close(stopped)
+			}()
+
+			if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			waitForStart(t, exec)
+
+			cancel()
+			waitFor(t, func() bool {
+				pool.mu.Lock()
+				defer pool.mu.Unlock()
+				return pool.draining
+			})
+			if tt.finish {
+				close(exec.releaseCh)
+			}
+			select {
+			case <-stopped:
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out waiting for the pool to drain")
+			}
+
+			updated := &corev1.Pod{}
+			if err := reconciler.Get(context.Background(), req.NamespacedName, updated); err != nil {
+				t.Fatalf("failed to get pod: %v", err)
+			}
+			if got := reconciler.isConditionTrue(updated, webhook.ConditionTypeWarmupReady); got != tt.wantCondition {
+				t.Errorf("warmup condition True = %v, want %v", got, tt.wantCondition)
+			}
+			progress := updated.Annotations[webhook.AnnotationWarmupProgress]
+			if tt.wantProgress == "" && progress != "" {
+				t.Errorf("progress annotation = %q, want none", progress)
+			}
+			if tt.wantProgress != "" && progress != tt.wantProgress {
+				t.Errorf("progress annotation = %q, want %q", progress, tt.wantProgress)
+			}
+
+			var sawStarted, sawHandedOff bool
+			for len(recorder.Events) > 0 {
+				ev := <-recorder.Events
+				if strings.Contains(ev, ReasonWarmupStarted) {
+					sawStarted = strings.HasSuffix(ev, tt.wantStarted)
+				}
+				if strings.Contains(ev, ReasonWarmupHandedOff) {
+					sawHandedOff = true
+				}
+			}
+			if !sawStarted {
+				t.Errorf("no %s event with message %q", ReasonWarmupStarted, tt.wantStarted)
+			}
+			if wantHandedOff := tt.wantProgress != ""; sawHandedOff != wantHandedOff {
+				t.Errorf("%s event recorded = %v, want %v", ReasonWarmupHandedOff, sawHandedOff, wantHandedOff)
+			}
+		})
+	}
+}
+
+// progressReportingExecutor completes step login, then records the pod's progress
+// annotation as a controller starting after a crash would find it.
+type progressReportingExecutor struct {
+	client   client.Client
+	pod      types.NamespacedName
+	recorded string
+}
+
+func (e *progressReportingExecutor) ExecuteScenario(ctx context.Context, config *warmup.Config, _ *v1alpha1.WarmupConfigSpec) *warmup.Result {
+	if config.OnProgress != nil {
+		config.OnProgress(&warmup.Progress{Steps: []string{"login"}})
+	}
+	pod := &corev1.Pod{}
+	if err := e.client.Get(ctx, e.pod, pod); err == nil {
+		e.recorded = pod.Annotations[webhook.AnnotationWarmupProgress]
+	}
+	return &warmup.Result{Success: true, RequestsCompleted: 2}
+}
+
+func TestPodReconciler_RecordsProgressPerStep(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	pod := makeReadyPod("test-pod", "default", map[string]string{webhook.AnnotationWarmupConfig: "catalog"})
+	cfg := &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default"},
+		Spec:       v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: "login"}, {Name: "browse"}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod, cfg).WithStatusSubresource(pod).Build()
+	exec := &progressReportingExecutor{client: c, pod: client.ObjectKeyFromObject(pod)}
+	r := &PodReconciler{
+		Client:           c,
+		Scheme:           scheme,
+		ScenarioExecutor: exec,
+		Recorder:         events.NewFakeRecorder(100),
+	}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	if want := `{"attempt":1,"steps":["login"]}`; exec.recorded != want {
+		t.Errorf("progress annotation during warmup = %q, want %q", exec.recorded, want)
+	}
+	updated := &corev1.Pod{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(pod), updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !r.isConditionTrue(updated, webhook.ConditionTypeWarmupReady) {
+		t.Error("warmup condition should be True")
+	}
+	if progress, ok := updated.Annotations[webhook.AnnotationWarmupProgress]; ok {
+		t.Errorf("progress annotation = %q after the warmup finished, want none", progress)
+	}
+}
+
+func TestPodReconciler_RecordsWorkloadMetrics(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels bool
+		want   metrics.Workload
+	}{
+		{name: "workload labels disabled"},
+		{
+			name:   "workload labels enabled",
+			labels: true,
+			want:   metrics.Workload{Kind: "Rollout", Name: "checkout"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+			_ = appsv1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+			pod := makeReadyPod("checkout-7d9f8-x2k4p", "default", nil)
+			pod.OwnerReferences = ownedBy("apps/v1", "ReplicaSet", "checkout-7d9f8", nil).OwnerReferences
+			rs := replicaSetOf("checkout-7d9f8", "argoproj.io/v1alpha1", "Rollout", "checkout")
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod, rs).WithStatusSubresource(pod).Build()
+			r := &PodReconciler{
+				Client: c,
+				Scheme: scheme,
+				WarmupExecutor: &warmup.MockExecutor{Result: &warmup.Result{
+					Success:             true,
+					RequestsCompleted:   10,
+					RequestsFailed:      1,
+					FirstRequestLatency: 800 * time.Millisecond,
+					FinalWindowLatency:  20 * time.Millisecond,
+					SpeedupRatio:        40,
+					Outcomes:            map[string]int{warmup.OutcomeSuccess: 10, "http_5xx": 1},
+				}},
+				Recorder:             events.NewFakeRecorder(100),
+				Workloads:            &WorkloadResolver{Reader: c},
+				WorkloadMetricLabels: tt.labels,
+			}
+			metrics.WarmupTotal.Reset()
+			metrics.WarmupRequestsTotal.Reset()
+			metrics.WarmupSpeedupRatio.Reset()
+
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if got := testutil.ToFloat64(metrics.WarmupTotal.WithLabelValues("default", tt.want.Kind, tt.want.Name, "success")); got != 1 {
+				t.Errorf("warmup_total{workload=%+v} = %v, want 1", tt.want, got)
+			}
+			if got := testutil.ToFloat64(metrics.WarmupRequestsTotal.WithLabelValues("default", tt.want.Kind, tt.want.Name, "http_5xx")); got != 1 {
+				t.Errorf("warmup_requests_total{workload=%+v,outcome=http_5xx} = %v, want 1", tt.want, got)
+			}
+			if got := testutil.CollectAndCount(metrics.WarmupSpeedupRatio); got != 1 {
+				t.Fatalf("expected 1 speedup ratio series, got %d", got)
+			}
+			// Looking up the expected series must not create a second one
+			metrics.WarmupSpeedupRatio.WithLabelValues("default", tt.want.Kind, tt.want.Name)
+			if got := testutil.CollectAndCount(metrics.WarmupSpeedupRatio); got != 1 {
+				t.Errorf("speedup ratio not recorded for workload %+v", tt.want)
+			}
+		})
+	}
+}
@@ -8,20 +8,30 @@ import (
 	"github.com/hhiroshell/kube-booster/pkg/webhook"
 )
 
-// HasReadinessGatePredicate filters events to only process pods with our readiness gate
-func HasReadinessGatePredicate() predicate.Predicate {
+// WarmupPodPredicate filters events to pods with our readiness gate and to pods that
+// warmup is enabled for but were created without the gate (for example because the
+// admission webhook was unavailable), so that the latter can be reported.
+// warmupEnabled resolves whether warmup is enabled for a pod.
+func WarmupPodPredicate(warmupEnabled func(pod *corev1.Pod) bool) predicate.Predicate {
+	match := func(obj interface{}) bool {
+		if hasReadinessGate(obj) {
+			return true
+		}
+		pod, ok := obj.(*corev1.Pod)
+		return ok && warmupEnabled(pod)
+	}
 	return predicate.Funcs{
 		CreateFunc: func(e event.CreateEvent) bool {
-			return hasReadinessGate(e.Object)
+			return match(e.Object)
 		},
 		UpdateFunc: func(e event.UpdateEvent) bool {
-			return hasReadinessGate(e.ObjectNew)
+			return match(e.ObjectNew)
 		},
 		DeleteFunc: func(e event.DeleteEvent) bool {
-			return false // We don't care about deletions
+			return match(e.Object)
 		},
 		GenericFunc: func(e event.GenericEvent) bool {
-			return hasReadinessGate(e.Object)
+			return match(e.Object)
 		},
 	}
 }
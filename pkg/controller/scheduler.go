@@ -0,0 +1,275 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+)
+
+// FairScheduler bounds the number of concurrent warmups and shares the available
+// slots fairly between namespaces.
+//
+// Waiting pods are queued per namespace. When a slot frees up it goes to the
+// namespace that has received the least service relative to its weight (stride
+// scheduling), so one namespace rolling out hundreds of pods cannot starve the
+// others. A namespace with weight 3 receives three slots for every one given to a
+// namespace with weight 1 while both have pods waiting.
+//
+// When pod priority is honored, the highest-priority waiting pod is always served
+// first; fairness applies among pods of equal priority.
+//
+// A nil receiver is valid and means unlimited concurrency.
+type FairScheduler struct {
+	mu            sync.Mutex
+	capacity      int
+	inUse         int
+	weights       map[string]int
+	honorPriority bool
+
+	queues map[string]*namespaceQueue
+	// vtime is the pass value of the most recently served namespace. Namespaces that
+	// start waiting join at vtime so they cannot bank credit while idle.
+	vtime float64
+	seq   uint64
+}
+
+// namespaceQueue holds the pods of one namespace waiting for a slot, ordered by
+// priority (highest first) and then arrival.
+type namespaceQueue struct {
+	waiters []*schedulerWaiter
+	pass    float64
+}
+
+type schedulerWaiter struct {
+	namespace string
+	priority  int32
+	seq       uint64
+	ready     chan struct{}
+}
+
+// FairSchedulerOption configures a FairScheduler.
+type FairSchedulerOption func(*FairScheduler)
+
+// WithNamespaceWeights sets per-namespace weights. Namespaces not listed have weight 1.
+func WithNamespaceWeights(weights map[string]int) FairSchedulerOption {
+	return func(s *FairScheduler) {
+		for ns, w := range weights {
+			if w > 0 {
+				s.weights[ns] = w
+			}
+		}
+	}
+}
+
+// WithPodPriority makes the scheduler serve higher-priority pods first, regardless
+// of namespace.
+func WithPodPriority() FairSchedulerOption {
+	return func(s *FairScheduler) {
+		s.honorPriority = true
+	}
+}
+
+// NewFairScheduler returns a FairScheduler allowing capacity concurrent warmups,
+// or nil if capacity <= 0 (unlimited).
+func NewFairScheduler(capacity int, opts ...FairSchedulerOption) *FairScheduler {
+	if capacity <= 0 {
+		return nil
+	}
+	s := &FairScheduler{
+		capacity: capacity,
+		weights:  map[string]int{},
+		queues:   map[string]*namespaceQueue{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Acquire blocks until a warmup slot is granted to a pod in namespace, or ctx is
+// done. priority is ignored unless the scheduler honors pod priority. On success
+// the caller must call Release when the warmup finishes.
+// A nil receiver is a no-op and returns nil immediately.
+func (s *FairScheduler) Acquire(ctx context.Context, namespace string, priority int32) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	if s.inUse < s.capacity && len(s.queues) == 0 {
+		s.inUse++
+		s.mu.Unlock()
+		return nil
+	}
+
+	if !s.honorPriority {
+		priority = 0
+	}
+	s.seq++
+	w := &schedulerWaiter{namespace: namespace, priority: priority, seq: s.seq, ready: make(chan struct{})}
+	s.enqueue(w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		select {
+		case <-w.ready:
+			// Granted after ctx was cancelled; hand the slot to the next waiter.
+			s.inUse--
+			s.dispatch()
+		default:
+			s.remove(w)
+		}
+		s.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Release returns a slot obtained with Acquire.
+// A nil receiver is a no-op.
+func (s *FairScheduler) Release() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inUse <= 0 {
+		panic("controller: FairScheduler released more slots than acquired")
+	}
+	s.inUse--
+	s.dispatch()
+}
+
+// enqueue adds w to its namespace queue. Must be called with s.mu held.
+func (s *FairScheduler) enqueue(w *schedulerWaiter) {
+	q, ok := s.queues[w.namespace]
+	if !ok {
+		q = &namespaceQueue{pass: s.vtime}
+		s.queues[w.namespace] = q
+	}
+	// Insert after every waiter of equal or higher priority to keep arrival order.
+	i := len(q.waiters)
+	for i > 0 && q.waiters[i-1].priority < w.priority {
+		i--
+	}
+	q.waiters = append(q.waiters, nil)
+	copy(q.waiters[i+1:], q.waiters[i:])
+	q.waiters[i] = w
+	metrics.SetWarmupQueueDepth(w.namespace, len(q.waiters))
+	s.dispatch()
+}
+
+// remove drops a cancelled waiter from its queue. Must be called with s.mu held.
+func (s *FairScheduler) remove(w *schedulerWaiter) {
+	q, ok := s.queues[w.namespace]
+	if !ok {
+		return
+	}
+	for i, qw := range q.waiters {
+		if qw == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			break
+		}
+	}
+	metrics.SetWarmupQueueDepth(w.namespace, len(q.waiters))
+	if len(q.waiters) == 0 {
+		delete(s.queues, w.namespace)
+	}
+}
+
+// dispatch grants free slots to waiting pods. Must be called with s.mu held.
+func (s *FairScheduler) dispatch() {
+	for s.inUse < s.capacity {
+		ns, q := s.next()
+		if q == nil {
+			return
+		}
+		w := q.waiters[0]
+		q.waiters = q.waiters[1:]
+		s.vtime = q.pass
+		q.pass += 1 / float64(s.weight(ns))
+		metrics.SetWarmupQueueDepth(ns, len(q.waiters))
+		if len(q.waiters) == 0 {
+			delete(s.queues, ns)
+		}
+		s.inUse++
+		close(w.ready)
+	}
+}
+
+// next returns the namespace queue to serve next: the highest head priority (when
+// honored), then the lowest pass, then the earliest arrival.
+func (s *FairScheduler) next() (string, *namespaceQueue) {
+	var (
+		bestNS string
+		best   *namespaceQueue
+	)
+	for ns, q := range s.queues {
+		if best == nil || s.before(q, best) {
+			bestNS, best = ns, q
+		}
+	}
+	return bestNS, best
+}
+
+// before reports whether queue a should be served before queue b.
+func (s *FairScheduler) before(a, b *namespaceQueue) bool {
+	ha, hb := a.waiters[0], b.waiters[0]
+	if ha.priority != hb.priority {
+		return ha.priority > hb.priority
+	}
+	if a.pass != b.pass {
+		return a.pass < b.pass
+	}
+	return ha.seq < hb.seq
+}
+
+// weight returns the scheduling weight of namespace.
+func (s *FairScheduler) weight(namespace string) int {
+	if w, ok := s.weights[namespace]; ok {
+		return w
+	}
+	return 1
+}
+
+// waiting returns the number of queued pods across all namespaces.
+func (s *FairScheduler) waiting() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, q := range s.queues {
+		n += len(q.waiters)
+	}
+	return n
+}
+
+// ParseNamespaceWeights parses comma-separated "<namespace>=<weight>" pairs
+// (e.g. "team-a=3,team-b=1"). Weights must be positive integers.
+func ParseNamespaceWeights(s string) (map[string]int, error) {
+	weights := map[string]int{}
+	if strings.TrimSpace(s) == "" {
+		return weights, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		ns, weightStr, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || ns == "" {
+			return nil, fmt.Errorf("namespace weight %q: expected format \"<namespace>=<weight>\"", part)
+		}
+		weight, err := strconv.Atoi(weightStr)
+		if err != nil {
+			return nil, fmt.Errorf("namespace weight %q: %w", part, err)
+		}
+		if weight < 1 {
+			return nil, fmt.Errorf("namespace weight %q: weight must be at least 1", part)
+		}
+		weights[ns] = weight
+	}
+	return weights, nil
+}
@@ -0,0 +1,240 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+)
+
+func TestFairScheduler_NilReceiver(t *testing.T) {
+	if s := NewFairScheduler(0); s != nil {
+		t.Fatal("NewFairScheduler(0) should return nil")
+	}
+	var s *FairScheduler
+	if err := s.Acquire(context.Background(), "default", 0); err != nil {
+		t.Errorf("nil FairScheduler.Acquire() = %v, want nil", err)
+	}
+	s.Release() // must not panic
+}
+
+// schedulerHarness queues Acquire calls one at a time so arrival order is
+// deterministic, and records the namespaces in the order they are granted.
+type schedulerHarness struct {
+	t     *testing.T
+	s     *FairScheduler
+	mu    sync.Mutex
+	order []string
+	wg    sync.WaitGroup
+}
+
+func (h *schedulerHarness) enqueue(namespace string, priority int32) {
+	h.t.Helper()
+	want := h.s.waiting() + 1
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		if err := h.s.Acquire(context.Background(), namespace, priority); err != nil {
+			h.t.Errorf("Acquire(%s) error = %v", namespace, err)
+			return
+		}
+		h.mu.Lock()
+		h.order = append(h.order, namespace)
+		h.mu.Unlock()
+	}()
+	waitFor(h.t, func() bool { return h.s.waiting() == want })
+}
+
+// drain releases slots one at a time until every queued pod has been granted.
+func (h *schedulerHarness) drain(n int) []string {
+	h.t.Helper()
+	for i := 1; i <= n; i++ {
+		h.s.Release()
+		waitFor(h.t, func() bool {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			return len(h.order) == i
+		})
+	}
+	h.wg.Wait()
+	return h.order
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFairScheduler_RoundRobinsNamespaces(t *testing.T) {
+	s := NewFairScheduler(1)
+	if err := s.Acquire(context.Background(), "busy", 0); err != nil {
+		t.Fatalf("initial Acquire() error = %v", err)
+	}
+
+	h := &schedulerHarness{t: t, s: s}
+	// A large rollout in "busy" arrives before a single pod in "quiet".
+	for range 4 {
+		h.enqueue("busy", 0)
+	}
+	h.enqueue("quiet", 0)
+
+	order := h.drain(5)
+	if order[1] != "quiet" {
+		t.Errorf("grant order = %v, want quiet served second instead of after the whole busy backlog", order)
+	}
+}
+
+func TestFairScheduler_NamespaceWeights(t *testing.T) {
+	s := NewFairScheduler(1, WithNamespaceWeights(map[string]int{"heavy": 3}))
+	if err := s.Acquire(context.Background(), "setup", 0); err != nil {
+		t.Fatalf("initial Acquire() error = %v", err)
+	}
+
+	h := &schedulerHarness{t: t, s: s}
+	for range 6 {
+		h.enqueue("light", 0)
+	}
+	for range 6 {
+		h.enqueue("heavy", 0)
+	}
+
+	order := h.drain(12)
+	counts := map[string]int{}
+	for _, ns := range order[:8] {
+		counts[ns]++
+	}
+	if counts["heavy"] != 6 || counts["light"] != 2 {
+		t.Errorf("first 8 grants = %v (heavy=%d, light=%d), want heavy=6, light=2", order[:8], counts["heavy"], counts["light"])
+	}
+}
+
+func TestFairScheduler_PodPriority(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []FairSchedulerOption
+		wantFirstNS  string
+		wantSecondNS string
+		wantThirdNS  string
+	}{
+		{
+			name:         "priority honored",
+			opts:         []FairSchedulerOption{WithPodPriority()},
+			wantFirstNS:  "critical",
+			wantSecondNS: "batch",
+			wantThirdNS:  "batch",
+		},
+		{
+			name:         "priority ignored by default",
+			wantFirstNS:  "batch",
+			wantSecondNS: "critical",
+			wantThirdNS:  "batch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFairScheduler(1, tt.opts...)
+			if err := s.Acquire(context.Background(), "setup", 0); err != nil {
+				t.Fatalf("initial Acquire() error = %v", err)
+			}
+
+			h := &schedulerHarness{t: t, s: s}
+			h.enqueue("batch", 0)
+			h.enqueue("batch", 0)
+			h.enqueue("critical", 1000)
+
+			order := h.drain(3)
+			want := []string{tt.wantFirstNS, tt.wantSecondNS, tt.wantThirdNS}
+			for i := range want {
+				if order[i] != want[i] {
+					t.Fatalf("grant order = %v, want %v", order, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFairScheduler_ContextCancelledWhileQueued(t *testing.T) {
+	metrics.WarmupQueueDepth.Reset()
+
+	s := NewFairScheduler(1)
+	if err := s.Acquire(context.Background(), "default", 0); err != nil {
+		t.Fatalf("initial Acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+	go func() { errCh <- s.Acquire(ctx, "team-a", 0) }()
+	waitFor(t, func() bool { return s.waiting() == 1 })
+
+	if depth := testutil.ToFloat64(metrics.WarmupQueueDepth.WithLabelValues("team-a")); depth != 1 {
+		t.Errorf("queue depth while waiting = %v, want 1", depth)
+	}
+
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire() error = %v, want %v", err, context.Canceled)
+	}
+	if n := s.waiting(); n != 0 {
+		t.Errorf("waiting() after cancel = %d, want 0", n)
+	}
+	if depth := testutil.ToFloat64(metrics.WarmupQueueDepth.WithLabelValues("team-a")); depth != 0 {
+		t.Errorf("queue depth after cancel = %v, want 0", depth)
+	}
+
+	// The held slot is still usable after the cancelled waiter left.
+	s.Release()
+	if err := s.Acquire(context.Background(), "team-b", 0); err != nil {
+		t.Errorf("Acquire() after release error = %v", err)
+	}
+}
+
+func TestParseNamespaceWeights(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string]int
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: map[string]int{}},
+		{name: "multiple", input: "team-a=3, team-b=1", want: map[string]int{"team-a": 3, "team-b": 1}},
+		{name: "missing weight", input: "team-a", wantErr: true},
+		{name: "non-numeric weight", input: "team-a=high", wantErr: true},
+		{name: "zero weight", input: "team-a=0", wantErr: true},
+		{name: "empty namespace", input: "=2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseNamespaceWeights(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseNamespaceWeights(%q) expected error, got %v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseNamespaceWeights(%q) unexpected error: %v", tt.input, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseNamespaceWeights(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			for ns, w := range tt.want {
+				if got[ns] != w {
+					t.Errorf("weight[%s] = %d, want %d", ns, got[ns], w)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+// maxIncludeDepth is how deeply includes and stepsFrom references may nest below
+// the config a pod references.
+const maxIncludeDepth = 5
+
+// lookupWarmupConfig fetches the scenario that config references for pod: a
+// WarmupConfig in the pod's namespace, or a ClusterWarmupConfig that allows the
+// namespace. ns is the pod's Namespace, used for the ClusterWarmupConfig namespace
+// selector, and may be nil. Includes and stepsFrom references are expanded, and
+// resolved describes each step of the expanded scenario and the config it came from;
+// it is nil if the config references no other config. The returned error reads as
+// a complete event message.
+func (r *PodReconciler) lookupWarmupConfig(ctx context.Context, pod *corev1.Pod, config *warmup.Config, ns *corev1.Namespace) (spec *v1alpha1.WarmupConfigSpec, resolved []string, err error) {
+	ref := v1alpha1.WarmupConfigReference{Kind: config.WarmupConfigKind, Name: config.WarmupConfigName}
+	root, err := r.getWarmupConfig(ctx, pod, ref, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !referencesConfigs(root) {
+		return root, nil, nil
+	}
+
+	x := &warmupConfigExpander{r: r, pod: pod, ns: ns}
+	if err := x.expand(ctx, ref, root); err != nil {
+		return nil, nil, err
+	}
+	expanded := *root
+	expanded.Includes = nil
+	expanded.Steps = x.steps
+	expanded.Parameters = x.params
+	expanded.PersistVariables = x.persist
+	return &expanded, x.resolved, nil
+}
+
+// getWarmupConfig fetches the WarmupConfig or ClusterWarmupConfig that ref names,
+// without expanding it.
+func (r *PodReconciler) getWarmupConfig(ctx context.Context, pod *corev1.Pod, ref v1alpha1.WarmupConfigReference, ns *corev1.Namespace) (*v1alpha1.WarmupConfigSpec, error) {
+	if ref.Kind != webhook.KindClusterWarmupConfig {
+		warmupCfg := &v1alpha1.WarmupConfig{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: pod.Namespace}, warmupCfg); err != nil {
+			return nil, fmt.Errorf("WarmupConfig %q not found: %w", ref.Name, err)
+		}
+		return &warmupCfg.Spec, nil
+	}
+
+	clusterCfg := &v1alpha1.ClusterWarmupConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name}, clusterCfg); err != nil {
+		return nil, fmt.Errorf("ClusterWarmupConfig %q not found: %w", ref.Name, err)
+	}
+	if !clusterWarmupConfigAllows(clusterCfg, pod.Namespace, ns) {
+		return nil, fmt.Errorf("ClusterWarmupConfig %q does not allow namespace %q (see its allowedNamespaces and namespaceSelector)",
+			ref.Name, pod.Namespace)
+	}
+	return &clusterCfg.Spec.WarmupConfigSpec, nil
+}
+
+// warmupConfigExpander flattens a config's includes and stepsFrom references into a
+// single list of steps.
+type warmupConfigExpander struct {
+	r   *PodReconciler
+	pod *corev1.Pod
+	ns  *corev1.Namespace
+
+	chain    []string // configs being expanded, outermost first
+	steps    []v1alpha1.WarmupStep
+	resolved []string
+	params   []v1alpha1.WarmupParameter
+	persist  []string
+}
+
+// expand appends the steps of spec, the config ref names, with its includes first
+// and every stepsFrom step replaced by the referenced steps. Parameters and persisted
+// variables are merged; the first declaration of a parameter wins, so the config
+// the pod references overrides the configs it includes.
+func (x *warmupConfigExpander) expand(ctx context.Context, ref v1alpha1.WarmupConfigReference, spec *v1alpha1.WarmupConfigSpec) error {
+	name := refString(ref)
+	if slices.Contains(x.chain, name) {
+		return fmt.Errorf("include cycle: %s -> %s", strings.Join(x.chain, " -> "), name)
+	}
+	if len(x.chain) > maxIncludeDepth {
+		return fmt.Errorf("includes nested more than %d levels deep: %s -> %s",
+			maxIncludeDepth, strings.Join(x.chain, " -> "), name)
+	}
+	x.chain = append(x.chain, name)
+	defer func() { x.chain = x.chain[:len(x.chain)-1] }()
+
+	for _, p := range spec.Parameters {
+		if !slices.ContainsFunc(x.params, func(q v1alpha1.WarmupParameter) bool { return q.Name == p.Name }) {
+			x.params = append(x.params, p)
+		}
+	}
+	for _, v := range spec.PersistVariables {
+		if !slices.Contains(x.persist, v) {
+			x.persist = append(x.persist, v)
+		}
+	}
+
+	for _, inc := range spec.Includes {
+		if err := x.expandRef(ctx, name, inc); err != nil {
+			return err
+		}
+	}
+	for _, step := range spec.Steps {
+		if step.StepsFrom != nil {
+			if err := x.expandRef(ctx, name, *step.StepsFrom); err != nil {
+				return err
+			}
+			continue
+		}
+		x.steps = append(x.steps, step)
+		desc := warmup.StepName(step, len(x.steps)-1)
+		if len(x.chain) > 1 {
+			desc += " (" + name + ")"
+		}
+		x.resolved = append(x.resolved, desc)
+	}
+	return nil
+}
+
+// expandRef fetches the config that from references and expands it.
+func (x *warmupConfigExpander) expandRef(ctx context.Context, from string, ref v1alpha1.WarmupConfigReference) error {
+	spec, err := x.r.getWarmupConfig(ctx, x.pod, ref, x.ns)
+	if err != nil {
+		return fmt.Errorf("%s references %s: %w", from, refString(ref), err)
+	}
+	return x.expand(ctx, ref, spec)
+}
+
+// referencesConfigs reports whether spec includes or takes steps from other configs.
+func referencesConfigs(spec *v1alpha1.WarmupConfigSpec) bool {
+	return len(spec.Includes) > 0 || slices.ContainsFunc(spec.Steps, func(s v1alpha1.WarmupStep) bool {
+		return s.StepsFrom != nil
+	})
+}
+
+// refString returns ref as "<kind>/<name>", the form accepted by the warmup-config
+// annotation.
+func refString(ref v1alpha1.WarmupConfigReference) string {
+	kind := ref.Kind
+	if kind == "" {
+		kind = webhook.KindWarmupConfig
+	}
+	return kind + "/" + ref.Name
+}
+
+// clusterWarmupConfigAllows reports whether pods in namespace may use cfg: the
+// namespace is listed in AllowedNamespaces or its labels match NamespaceSelector.
+// With neither set, every namespace is denied. ns may be nil, in which case only
+// AllowedNamespaces is consulted.
+func clusterWarmupConfigAllows(cfg *v1alpha1.ClusterWarmupConfig, namespace string, ns *corev1.Namespace) bool {
+	if slices.Contains(cfg.Spec.AllowedNamespaces, namespace) {
+		return true
+	}
+	if cfg.Spec.NamespaceSelector == nil || ns == nil {
+		return false
+	}
+	selector, err := metav1.LabelSelectorAsSelector(cfg.Spec.NamespaceSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(ns.Labels))
+}
+
+// resolveWarmupParams replaces config.Params with the values of the parameters spec
+// declares. The returned error reads as a complete event message.
+func resolveWarmupParams(config *warmup.Config, spec *v1alpha1.WarmupConfigSpec) error {
+	params, err := warmup.ResolveParams(spec, config.Params)
+	if err != nil {
+		return fmt.Errorf("%s %q: %w", config.WarmupConfigKind, config.WarmupConfigName, err)
+	}
+	config.Params = params
+	return nil
+}
@@ -0,0 +1,436 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestPodReconciler_lookupWarmupConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	steps := func(name string) v1alpha1.WarmupConfigSpec {
+		return v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: name}}}
+	}
+	clusterCfg := func(name string, allowed []string, selector *metav1.LabelSelector) *v1alpha1.ClusterWarmupConfig {
+		return &v1alpha1.ClusterWarmupConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.ClusterWarmupConfigSpec{
+				WarmupConfigSpec:  steps(name),
+				AllowedNamespaces: allowed,
+				NamespaceSelector: selector,
+			},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "team-a"}, Spec: steps("local")},
+		clusterCfg("listed", []string{"team-a"}, nil),
+		clusterCfg("selected", nil, &metav1.LabelSelector{MatchLabels: map[string]string{"runtime": "jvm"}}),
+		clusterCfg("everyone", nil, &metav1.LabelSelector{}),
+		clusterCfg("unshared", nil, nil),
+	).Build()
+	r := &PodReconciler{Client: c}
+
+	jvmNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"runtime": "jvm"}}}
+	tests := []struct {
+		name     string
+		ref      string
+		ns       *corev1.Namespace
+		wantStep string
+		wantErr  string
+	}{
+		{name: "namespaced WarmupConfig", ref: "local", wantStep: "local"},
+		{name: "explicit WarmupConfig kind", ref: "WarmupConfig/local", wantStep: "local"},
+		{name: "missing WarmupConfig", ref: "gone", wantErr: `WarmupConfig "gone" not found`},
+		{name: "allowed by list", ref: "ClusterWarmupConfig/listed", wantStep: "listed"},
+		{name: "allowed by selector", ref: "ClusterWarmupConfig/selected", ns: jvmNS, wantStep: "selected"},
+		{name: "selector without namespace object", ref: "ClusterWarmupConfig/selected",
+			wantErr: `ClusterWarmupConfig "selected" does not allow namespace "team-a"`},
+		{name: "empty selector allows every namespace", ref: "ClusterWarmupConfig/everyone", ns: jvmNS, wantStep: "everyone"},
+		{name: "denied by default", ref: "ClusterWarmupConfig/unshared", ns: jvmNS,
+			wantErr: `ClusterWarmupConfig "unshared" does not allow namespace "team-a"`},
+		{name: "missing ClusterWarmupConfig", ref: "ClusterWarmupConfig/gone", wantErr: `ClusterWarmupConfig "gone" not found`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makeReadyPod("test-pod", "team-a", map[string]string{webhook.AnnotationWarmupConfig: tt.ref})
+			config, err := warmup.ParseConfig(pod)
+			if err != nil {
+				t.Fatalf("ParseConfig() error = %v", err)
+			}
+			spec, _, err := r.lookupWarmupConfig(context.Background(), pod, config, tt.ns)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("lookupWarmupConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupWarmupConfig() error = %v", err)
+			}
+			if spec.Steps[0].Name != tt.wantStep {
+				t.Errorf("lookupWarmupConfig() step = %q, want %q", spec.Steps[0].Name, tt.wantStep)
+			}
+		})
+	}
+}
+
+func TestPodReconciler_ClusterWarmupConfigDenied(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	pod := makeReadyPod("test-pod", "tenant", map[string]string{
+		webhook.AnnotationWarmupConfig: "ClusterWarmupConfig/jvm-spring",
+	})
+	cfg := &v1alpha1.ClusterWarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "jvm-spring"},
+		Spec: v1alpha1.ClusterWarmupConfigSpec{
+			WarmupConfigSpec:  v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: "s1"}}},
+			AllowedNamespaces: []string{"platform"},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pod, cfg).
+		WithStatusSubresource(pod).
+		Build()
+	fakeRec := events.NewFakeRecorder(100)
+	mockScenario := &warmup.MockScenarioExecutor{}
+	reconciler := &PodReconciler{
+		Client:           fakeClient,
+		Scheme:           scheme,
+		ScenarioExecutor: mockScenario,
+		Recorder:         fakeRec,
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if mockScenario.Called {
+		t.Error("ScenarioExecutor should not run a ClusterWarmupConfig the namespace may not use")
+	}
+
+	updated := &corev1.Pod{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !reconciler.isConditionTrue(updated, webhook.ConditionTypeWarmupReady) {
+		t.Error("warmup condition should be True (fail-open)")
+	}
+
+	close(fakeRec.Events)
+	var found bool
+	for ev := range fakeRec.Events {
+		if strings.Contains(ev, "Warning") && strings.Contains(ev, ReasonWarmupFailed) &&
+			strings.Contains(ev, `does not allow namespace "tenant"`) {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected Warning/WarmupFailed event saying the namespace is not allowed")
+	}
+}
+
+func TestPodReconciler_WarmupConfigParams(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	cfg := &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default"},
+		Spec: v1alpha1.WarmupConfigSpec{
+			Steps: []v1alpha1.WarmupStep{{Name: "s1"}},
+			Parameters: []v1alpha1.WarmupParameter{
+				{Name: "tenant", Required: true},
+				{Name: "region", Default: "us"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantParams  map[string]string
+		wantEvent   string
+	}{
+		{
+			name: "parameters resolved",
+			annotations: map[string]string{
+				webhook.AnnotationWarmupConfigParams:          `{"tenant":"acme"}`,
+				webhook.AnnotationWarmupParamPrefix + "extra": "ignored",
+			},
+			wantParams: map[string]string{"tenant": "acme", "region": "us"},
+		},
+		{
+			name:        "required parameter missing",
+			annotations: map[string]string{webhook.AnnotationWarmupParamPrefix + "region": "eu"},
+			wantEvent:   `WarmupConfig "catalog": required parameters not set: tenant`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := map[string]string{webhook.AnnotationWarmupConfig: "catalog"}
+			for k, v := range tt.annotations {
+				annotations[k] = v
+			}
+			pod := makeReadyPod("test-pod", "default", annotations)
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(pod, cfg.DeepCopy()).
+				WithStatusSubresource(pod).
+				Build()
+			fakeRec := events.NewFakeRecorder(100)
+			mockScenario := &warmup.MockScenarioExecutor{}
+			reconciler := &PodReconciler{
+				Client:           fakeClient,
+				Scheme:           scheme,
+				ScenarioExecutor: mockScenario,
+				Recorder:         fakeRec,
+			}
+
+			_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+				NamespacedName: types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace},
+			})
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			if tt.wantEvent == "" {
+				if !mockScenario.Called {
+					t.Fatal("ScenarioExecutor should have run")
+				}
+				if !maps.Equal(mockScenario.Config.Params, tt.wantParams) {
+					t.Errorf("Params = %v, want %v", mockScenario.Config.Params, tt.wantParams)
+				}
+				return
+			}
+
+			if mockScenario.Called {
+				t.Error("ScenarioExecutor should not run without the required parameters")
+			}
+			close(fakeRec.Events)
+			var found bool
+			for ev := range fakeRec.Events {
+				if strings.Contains(ev, "Warning") && strings.Contains(ev, ReasonWarmupFailed) && strings.Contains(ev, tt.wantEvent) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("expected Warning/WarmupFailed event containing %q", tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestPodReconciler_lookupWarmupConfigIncludes(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	ref := func(kind, name string) v1alpha1.WarmupConfigReference {
+		return v1alpha1.WarmupConfigReference{Kind: kind, Name: name}
+	}
+	local := func(name string, spec v1alpha1.WarmupConfigSpec) *v1alpha1.WarmupConfig {
+		return &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "team-a"}, Spec: spec}
+	}
+	step := func(name string) v1alpha1.WarmupStep { return v1alpha1.WarmupStep{Name: name} }
+
+	objs := []client.Object{
+		&v1alpha1.ClusterWarmupConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "auth"},
+			Spec: v1alpha1.ClusterWarmupConfigSpec{
+				WarmupConfigSpec: v1alpha1.WarmupConfigSpec{
+					Steps:      []v1alpha1.WarmupStep{step("login")},
+					Parameters: []v1alpha1.WarmupParameter{{Name: "user", Default: "warmup"}, {Name: "tenant", Required: true}},
+				},
+				AllowedNamespaces: []string{"team-a"},
+			},
+		},
+		&v1alpha1.ClusterWarmupConfig{
+			ObjectMeta: metav1.ObjectMeta{Name: "private"},
+			Spec:       v1alpha1.ClusterWarmupConfigSpec{WarmupConfigSpec: v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{step("secret")}}},
+		},
+		local("health", v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{}}, PersistVariables: []string{"region"}}),
+		local("app", v1alpha1.WarmupConfigSpec{
+			Timeout:    "90s",
+			Includes:   []v1alpha1.WarmupConfigReference{ref(webhook.KindClusterWarmupConfig, "auth")},
+			Parameters: []v1alpha1.WarmupParameter{{Name: "tenant", Default: "acme"}},
+			Steps: []v1alpha1.WarmupStep{
+				step("load-cache"),
+				{StepsFrom: &v1alpha1.WarmupConfigReference{Name: "health"}},
+			},
+		}),
+		local("uses-private", v1alpha1.WarmupConfigSpec{Includes: []v1alpha1.WarmupConfigReference{ref(webhook.KindClusterWarmupConfig, "private")}}),
+		local("uses-missing", v1alpha1.WarmupConfigSpec{Includes: []v1alpha1.WarmupConfigReference{ref("", "gone")}}),
+		local("cycle-a", v1alpha1.WarmupConfigSpec{Includes: []v1alpha1.WarmupConfigReference{ref("", "cycle-b")}}),
+		local("cycle-b", v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{StepsFrom: &v1alpha1.WarmupConfigReference{Name: "cycle-a"}}}}),
+		local("twice", v1alpha1.WarmupConfigSpec{Includes: []v1alpha1.WarmupConfigReference{ref("", "health"), ref("", "health")}}),
+	}
+	// deep-0 includes deep-1, ..., deep-6; only the first five levels are allowed.
+	for i := range 7 {
+		spec := v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{step(fmt.Sprintf("level-%d", i))}}
+		if i < 6 {
+			spec.Includes = []v1alpha1.WarmupConfigReference{ref("", fmt.Sprintf("deep-%d", i+1))}
+		}
+		objs = append(objs, local(fmt.Sprintf("deep-%d", i), spec))
+	}
+	r := &PodReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+
+	tests := []struct {
+		name         string
+		ref          string
+		wantResolved []string
+		wantErr      string
+	}{
+		{
+			name:         "includes run first and stepsFrom is spliced in place",
+			ref:          "app",
+			wantResolved: []string{"login (ClusterWarmupConfig/auth)", "load-cache", "step-3 (WarmupConfig/health)"},
+		},
+		{
+			name:         "a config may be included more than once",
+			ref:          "twice",
+			wantResolved: []string{"step-1 (WarmupConfig/health)", "step-2 (WarmupConfig/health)"},
+		},
+		{name: "no references", ref: "health"},
+		{name: "five levels deep", ref: "deep-1", wantResolved: []string{
+			"level-6 (WarmupConfig/deep-6)", "level-5 (WarmupConfig/deep-5)", "level-4 (WarmupConfig/deep-4)",
+			"level-3 (WarmupConfig/deep-3)", "level-2 (WarmupConfig/deep-2)", "level-1",
+		}},
+		{name: "too deep", ref: "deep-0", wantErr: "includes nested more than 5 levels deep: WarmupConfig/deep-0 -> "},
+		{name: "cycle", ref: "cycle-a", wantErr: "include cycle: WarmupConfig/cycle-a -> WarmupConfig/cycle-b -> WarmupConfig/cycle-a"},
+		{name: "missing include", ref: "uses-missing",
+			wantErr: `WarmupConfig/uses-missing references WarmupConfig/gone: WarmupConfig "gone" not found`},
+		{name: "included ClusterWarmupConfig must allow the namespace", ref: "uses-private",
+			wantErr: `ClusterWarmupConfig "private" does not allow namespace "team-a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makeReadyPod("test-pod", "team-a", map[string]string{webhook.AnnotationWarmupConfig: tt.ref})
+			config, err := warmup.ParseConfig(pod)
+			if err != nil {
+				t.Fatalf("ParseConfig() error = %v", err)
+			}
+			spec, resolved, err := r.lookupWarmupConfig(context.Background(), pod, config, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("lookupWarmupConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("lookupWarmupConfig() error = %v", err)
+			}
+			if !slices.Equal(resolved, tt.wantResolved) {
+				t.Errorf("resolved = %q, want %q", resolved, tt.wantResolved)
+			}
+			if len(spec.Steps) != max(len(tt.wantResolved), 1) {
+				t.Errorf("len(Steps) = %d, want %d", len(spec.Steps), len(tt.wantResolved))
+			}
+			if len(spec.Includes) != 0 && tt.wantResolved != nil {
+				t.Errorf("expanded spec still has includes: %v", spec.Includes)
+			}
+		})
+	}
+
+	t.Run("root settings and merged parameters", func(t *testing.T) {
+		pod := makeReadyPod("test-pod", "team-a", map[string]string{webhook.AnnotationWarmupConfig: "app"})
+		config, err := warmup.ParseConfig(pod)
+		if err != nil {
+			t.Fatalf("ParseConfig() error = %v", err)
+		}
+		spec, _, err := r.lookupWarmupConfig(context.Background(), pod, config, nil)
+		if err != nil {
+			t.Fatalf("lookupWarmupConfig() error = %v", err)
+		}
+		if spec.Timeout != "90s" {
+			t.Errorf("Timeout = %q, want the including config's 90s", spec.Timeout)
+		}
+		wantParams := []v1alpha1.WarmupParameter{{Name: "tenant", Default: "acme"}, {Name: "user", Default: "warmup"}}
+		if !slices.Equal(spec.Parameters, wantParams) {
+			t.Errorf("Parameters = %v, want %v", spec.Parameters, wantParams)
+		}
+		if !slices.Equal(spec.PersistVariables, []string{"region"}) {
+			t.Errorf("PersistVariables = %v, want [region]", spec.PersistVariables)
+		}
+	})
+}
+
+func TestPodReconciler_WarmupConfigResolvedEvent(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	pod := makeReadyPod("test-pod", "default", map[string]string{webhook.AnnotationWarmupConfig: "app"})
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(pod,
+			&v1alpha1.WarmupConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "auth", Namespace: "default"},
+				Spec:       v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: "login"}}},
+			},
+			&v1alpha1.WarmupConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+				Spec: v1alpha1.WarmupConfigSpec{
+					Includes: []v1alpha1.WarmupConfigReference{{Name: "auth"}},
+					Steps:    []v1alpha1.WarmupStep{{Name: "load-cache"}},
+				},
+			}).
+		WithStatusSubresource(pod).
+		Build()
+	fakeRec := events.NewFakeRecorder(100)
+	mockScenario := &warmup.MockScenarioExecutor{}
+	reconciler := &PodReconciler{
+		Client:           fakeClient,
+		Scheme:           scheme,
+		ScenarioExecutor: mockScenario,
+		Recorder:         fakeRec,
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !mockScenario.Called {
+		t.Fatal("ScenarioExecutor should have run")
+	}
+
+	close(fakeRec.Events)
+	want := "Normal " + ReasonWarmupConfigResolved + " Resolved app to 2 steps: login (WarmupConfig/auth), load-cache"
+	var found bool
+	for ev := range fakeRec.Events {
+		if ev == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected event %q", want)
+	}
+}
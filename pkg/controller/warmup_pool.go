@@ -0,0 +1,292 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// WarmupPool runs warmups in the background so that reconcile workers are not
+// blocked for the duration of a warmup (including the wait for a concurrency slot).
+//
+// Jobs are tracked by pod UID, so at most one warmup runs per pod. When a job
+// finishes, the pod is sent through Source to be reconciled again, and Reconcile
+// applies the stored outcome. Concurrency is bounded by the reconciler's
+// FairScheduler, which each job waits on before executing.
+//
+// The pool is a manager.Runnable. When the manager shuts down it stops accepting
+// jobs, gives running ones the shutdown grace period to finish, then cancels the
+// rest and hands every remaining job to the reconciler (see PodReconciler.handoffWarmup).
+type WarmupPool struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	gracePeriod time.Duration
+	// handoff is called during shutdown for each job the pool could not see through.
+	// interrupted is set if the job was still running when the grace period ended.
+	handoff func(ctx context.Context, job *warmupJob, interrupted bool)
+
+	mu       sync.Mutex
+	jobs     map[types.UID]*warmupJob
+	draining bool
+
+	events chan event.TypedGenericEvent[*corev1.Pod]
+	wg     sync.WaitGroup
+}
+
+const (
+	// DefaultShutdownGracePeriod is how long in-flight warmups may keep running
+	// after the controller is asked to shut down.
+	DefaultShutdownGracePeriod = 20 * time.Second
+
+	// handoffTimeout bounds the API calls made to hand off jobs during shutdown.
+	handoffTimeout = 10 * time.Second
+)
+
+// WarmupPoolOption configures a WarmupPool.
+type WarmupPoolOption func(*WarmupPool)
+
+// WithShutdownGracePeriod sets how long in-flight warmups may keep running after
+// shutdown begins before they are interrupted and handed off.
+func WithShutdownGracePeriod(d time.Duration) WarmupPoolOption {
+	return func(p *WarmupPool) {
+		p.gracePeriod = d
+	}
+}
+
+// warmupJob is one pod's background warmup.
+type warmupJob struct {
+	pod    *corev1.Pod // snapshot taken when the job was submitted
+	podIP  string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// outcome is set before done is closed. It is nil if the job was cancelled
+	// before it could run.
+	outcome *warmupOutcome
+
+	// started is set once the job has been granted a concurrency slot and the
+	// warmup attempt has begun.
+	started atomic.Bool
+}
+
+// warmupJobKey is the context key under which a job is stored in its own context.
+type warmupJobKey struct{}
+
+// markWarmupStarted records that the job running under ctx has begun its warmup
+// attempt. It is a no-op outside the pool.
+func markWarmupStarted(ctx context.Context) {
+	if job, ok := ctx.Value(warmupJobKey{}).(*warmupJob); ok {
+		job.started.Store(true)
+	}
+}
+
+// finished reports whether the job has ended, and its outcome if so.
+func (j *warmupJob) finished() (*warmupOutcome, bool) {
+	select {
+	case <-j.done:
+		return j.outcome, true
+	default:
+		return nil, false
+	}
+}
+
+// NewWarmupPool returns an empty WarmupPool.
+func NewWarmupPool(opts ...WarmupPoolOption) *WarmupPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &WarmupPool{
+		ctx:         ctx,
+		cancel:      cancel,
+		gracePeriod: DefaultShutdownGracePeriod,
+		jobs:        map[types.UID]*warmupJob{},
+		events:      make(chan event.TypedGenericEvent[*corev1.Pod], 1024),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Start implements manager.Runnable. It blocks until ctx is done and then drains
+// the pool.
+func (p *WarmupPool) Start(ctx context.Context) error {
+	<-ctx.Done()
+	p.drain(log.FromContext(ctx).WithName("warmup-pool"))
+	return nil
+}
+
+// drain stops accepting jobs, waits up to the grace period for running jobs to
+// finish, and then cancels whatever is left and hands off every tracked job.
+func (p *WarmupPool) drain(logger logr.Logger) {
+	p.mu.Lock()
+	p.draining = true
+	running := len(p.jobs)
+	p.mu.Unlock()
+
+	logger.Info("shutting down, waiting for in-flight warmups", "jobs", running, "gracePeriod", p.gracePeriod)
+	if !p.wait(p.gracePeriod) {
+		logger.Info("shutdown grace period elapsed, interrupting remaining warmups")
+	}
+
+	p.mu.Lock()
+	jobs := p.jobs
+	p.jobs = map[types.UID]*warmupJob{}
+	p.mu.Unlock()
+
+	interrupted := map[*warmupJob]bool{}
+	for _, job := range jobs {
+		if _, done := job.finished(); !done {
+			interrupted[job] = true
+			job.cancel()
+		}
+	}
+	p.cancel()
+	// Let interrupted jobs unwind (release their slots, record metrics) first
+	p.wait(handoffTimeout)
+
+	if p.handoff == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), handoffTimeout)
+	defer cancel()
+	for _, job := range jobs {
+		p.handoff(log.IntoContext(ctx, logger), job, interrupted[job])
+	}
+}
+
+// wait blocks until all jobs have returned or timeout elapses, and reports which.
+func (p *WarmupPool) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Source returns a controller source that enqueues pods whose warmup has finished.
+func (p *WarmupPool) Source() source.Source {
+	return source.Channel(p.events, &handler.TypedEnqueueRequestForObject[*corev1.Pod]{})
+}
+
+// Submit starts run in the background for pod unless a job for the pod's UID is
+// already tracked or the pool is shutting down. It reports whether a new job was
+// started. The logger from ctx is carried over to the job; ctx itself is not, so
+// the job outlives the reconcile.
+func (p *WarmupPool) Submit(ctx context.Context, pod *corev1.Pod, run func(context.Context) *warmupOutcome) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.jobs[pod.UID]; ok || p.draining {
+		return false
+	}
+
+	job := &warmupJob{
+		pod:   pod.DeepCopy(),
+		podIP: pod.Status.PodIP,
+		done:  make(chan struct{}),
+	}
+	jobCtx, cancel := context.WithCancel(log.IntoContext(p.ctx, log.FromContext(ctx)))
+	jobCtx = context.WithValue(jobCtx, warmupJobKey{}, job)
+	job.cancel = cancel
+	p.jobs[pod.UID] = job
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		defer cancel()
+		job.outcome = run(jobCtx)
+		close(job.done)
+		p.notify(job)
+	}()
+	return true
+}
+
+// notify enqueues the job's pod for reconciliation, unless the job was cancelled
+// and is no longer tracked.
+func (p *WarmupPool) notify(job *warmupJob) {
+	p.mu.Lock()
+	current := p.jobs[job.pod.UID] == job
+	p.mu.Unlock()
+	if !current {
+		return
+	}
+	select {
+	case p.events <- event.TypedGenericEvent[*corev1.Pod]{Object: job.pod}:
+	case <-p.ctx.Done():
+	}
+}
+
+// Get returns the job tracked for uid, or nil.
+func (p *WarmupPool) Get(uid types.UID) *warmupJob {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.jobs[uid]
+}
+
+// Forget stops tracking job once its outcome has been applied. It is a no-op if a
+// different job has since been submitted for the same pod.
+func (p *WarmupPool) Forget(uid types.UID, job *warmupJob) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.jobs[uid] == job {
+		delete(p.jobs, uid)
+	}
+}
+
+// Cancel stops the job tracked for uid, if any, and discards it. It returns the
+// pod snapshot of the cancelled job, or nil if no job was still running (the
+// outcome of a finished job is simply dropped).
+func (p *WarmupPool) Cancel(uid types.UID) *corev1.Pod {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	job, ok := p.jobs[uid]
+	if !ok {
+		return nil
+	}
+	return p.cancelLocked(uid, job)
+}
+
+// CancelPod cancels the job for the pod with the given name, like Cancel. It is
+// used when the pod is gone and its UID is no longer known.
+func (p *WarmupPool) CancelPod(key types.NamespacedName) *corev1.Pod {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for uid, job := range p.jobs {
+		if job.pod.Namespace == key.Namespace && job.pod.Name == key.Name {
+			return p.cancelLocked(uid, job)
+		}
+	}
+	return nil
+}
+
+// cancelLocked cancels and untracks job. Must be called with p.mu held.
+func (p *WarmupPool) cancelLocked(uid types.UID, job *warmupJob) *corev1.Pod {
+	delete(p.jobs, uid)
+	if _, done := job.finished(); done {
+		return nil
+	}
+	job.cancel()
+	return job.pod
+}
+
+// Len returns the number of tracked jobs, running or awaiting reconciliation.
+func (p *WarmupPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.jobs)
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+)
+
+func TestWarmupPool_SubmitDeduplicatesByUID(t *testing.T) {
+	pool := NewWarmupPool()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default", UID: "uid-1"}}
+
+	release := make(chan struct{})
+	run := func(ctx context.Context) *warmupOutcome {
+		<-release
+		return &warmupOutcome{result: &warmup.Result{Success: true}}
+	}
+
+	if !pool.Submit(context.Background(), pod, run) {
+		t.Fatal("first Submit() = false, want true")
+	}
+	if pool.Submit(context.Background(), pod, run) {
+		t.Error("second Submit() for the same UID = true, want false")
+	}
+	job := pool.Get(pod.UID)
+	if _, done := job.finished(); done {
+		t.Error("job finished before run returned")
+	}
+
+	close(release)
+	select {
+	case <-pool.events:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for completion event")
+	}
+	outcome, done := job.finished()
+	if !done || outcome == nil || !outcome.result.Success {
+		t.Errorf("finished() = (%v, %v), want successful outcome", outcome, done)
+	}
+
+	// Forget only removes the job it was given.
+	pool.Forget(pod.UID, &warmupJob{})
+	if pool.Len() != 1 {
+		t.Error("Forget() with a stale job removed the current one")
+	}
+	pool.Forget(pod.UID, job)
+	if pool.Len() != 0 {
+		t.Errorf("Len() after Forget() = %d, want 0", pool.Len())
+	}
+}
+
+func TestWarmupPool_CancelPod(t *testing.T) {
+	pool := NewWarmupPool()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default", UID: "uid-1"}}
+
+	stopped := make(chan struct{})
+	pool.Submit(context.Background(), pod, func(ctx context.Context) *warmupOutcome {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	})
+
+	if got := pool.CancelPod(types.NamespacedName{Name: "other", Namespace: "default"}); got != nil {
+		t.Errorf("CancelPod() for an unknown pod = %v, want nil", got.Name)
+	}
+	if got := pool.CancelPod(types.NamespacedName{Name: "pod-1", Namespace: "default"}); got == nil || got.UID != pod.UID {
+		t.Fatalf("CancelPod() = %v, want snapshot of pod-1", got)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job context was not cancelled")
+	}
+	if pool.Len() != 0 {
+		t.Errorf("Len() after CancelPod() = %d, want 0", pool.Len())
+	}
+}
+
+func TestWarmupPool_DrainOnShutdown(t *testing.T) {
+	pool := NewWarmupPool(WithShutdownGracePeriod(100 * time.Millisecond))
+
+	var mu sync.Mutex
+	handedOff := map[string]bool{}
+	pool.handoff = func(ctx context.Context, job *warmupJob, interrupted bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		handedOff[job.pod.Name] = interrupted
+		if got, want := job.started.Load(), job.pod.Name == "running"; got != want {
+			t.Errorf("job %s started = %v, want %v", job.pod.Name, got, want)
+		}
+	}
+
+	finished := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "finished", Namespace: "default", UID: "uid-1"}}
+	pool.Submit(context.Background(), finished, func(ctx context.Context) *warmupOutcome {
+		return &warmupOutcome{result: &warmup.Result{Success: true}}
+	})
+	<-pool.events
+
+	running := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "default", UID: "uid-2"}}
+	started := make(chan struct{})
+	pool.Submit(context.Background(), running, func(ctx context.Context) *warmupOutcome {
+		markWarmupStarted(ctx)
+		close(started)
+		<-ctx.Done()
+		return &warmupOutcome{result: &warmup.Result{Success: false, Error: ctx.Err()}}
+	})
+	<-started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := pool.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	want := map[string]bool{"finished": false, "running": true}
+	if len(handedOff) != len(want) {
+		t.Fatalf("handed off %v, want %v", handedOff, want)
+	}
+	for name, interrupted := range want {
+		if handedOff[name] != interrupted {
+			t.Errorf("handoff(%s) interrupted = %v, want %v", name, handedOff[name], interrupted)
+		}
+	}
+	if pool.Len() != 0 {
+		t.Errorf("Len() after drain = %d, want 0", pool.Len())
+	}
+
+	late := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "late", Namespace: "default", UID: "uid-3"}}
+	if pool.Submit(context.Background(), late, func(ctx context.Context) *warmupOutcome { return nil }) {
+		t.Error("Submit() after shutdown = true, want false")
+	}
+}
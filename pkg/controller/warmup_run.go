@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+const (
+	// WarmupRunSourceAnnotations is the source kind of WarmupRuns of single-endpoint
+	// warmups configured by annotations, policies or the namespace.
+	WarmupRunSourceAnnotations = "Annotations"
+
+	// DefaultWarmupRunCleanupInterval is how often the WarmupRunCleaner looks for
+	// retained runs that have expired.
+	DefaultWarmupRunCleanupInterval = 10 * time.Minute
+
+	// maxWarmupRunNamePrefix caps the generated name prefix so that the random
+	// suffix still fits in an object name.
+	maxWarmupRunNamePrefix = 253 - 5
+)
+
+// newWarmupRun builds the WarmupRun that records a warmup of pod. config is nil
+// when the warmup config could not be parsed. A positive retention keeps the run
+// for that long after the warmup ended, instead of deleting it with the pod.
+func newWarmupRun(pod *corev1.Pod, attempt int, config *warmup.Config, result *warmup.Result,
+	start, end time.Time, retention time.Duration) *v1alpha1.WarmupRun {
+	prefix := fmt.Sprintf("-%d-", attempt)
+	name := pod.Name
+	if len(name)+len(prefix) > maxWarmupRunNamePrefix {
+		name = name[:maxWarmupRunNamePrefix-len(prefix)]
+	}
+
+	labels := make(map[string]string, len(pod.Labels)+1)
+	for k, v := range pod.Labels {
+		labels[k] = v
+	}
+	labels[webhook.LabelWarmupRunPodUID] = string(pod.UID)
+
+	run := &v1alpha1.WarmupRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name + prefix,
+			Namespace:    pod.Namespace,
+			Labels:       labels,
+		},
+		Spec: v1alpha1.WarmupRunSpec{
+			PodName:  pod.Name,
+			PodUID:   string(pod.UID),
+			NodeName: pod.Spec.NodeName,
+			Attempt:  int32(attempt),
+			Source:   v1alpha1.WarmupRunSource{Kind: WarmupRunSourceAnnotations},
+		},
+		Status: v1alpha1.WarmupRunStatus{
+			StartTime:         metav1.NewTime(start),
+			EndTime:           metav1.NewTime(end),
+			Duration:          metav1.Duration{Duration: result.TotalDuration},
+			Success:           result.Success,
+			Message:           result.Message,
+			RequestsCompleted: int32(result.RequestsCompleted),
+			RequestsFailed:    int32(result.RequestsFailed),
+		},
+	}
+
+	if retention > 0 {
+		retainUntil := metav1.NewTime(end.Add(retention))
+		run.Spec.RetainUntil = &retainUntil
+	} else {
+		run.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       pod.Name,
+			UID:        pod.UID,
+		}}
+	}
+
+	if config != nil {
+		if config.WarmupConfigName != "" {
+			run.Spec.Source.Kind = config.WarmupConfigKind
+			run.Spec.Source.Name = config.WarmupConfigName
+		}
+		run.Spec.Source.Settings = config.DescribeSources()
+	}
+
+	if result.Error != nil {
+		run.Status.Error = result.Error.Error()
+	}
+	run.Status.LatencyP50 = optionalDuration(result.LatencyP50)
+	run.Status.LatencyP99 = optionalDuration(result.LatencyP99)
+	run.Status.FirstRequestLatency = optionalDuration(result.FirstRequestLatency)
+	run.Status.FinalWindowLatency = optionalDuration(result.FinalWindowLatency)
+	for _, step := range result.Steps {
+		s := v1alpha1.WarmupRunStep{
+			Name:              step.Name,
+			RequestsCompleted: int32(step.RequestsCompleted),
+			RequestsFailed:    int32(step.RequestsFailed),
+			Duration:          metav1.Duration{Duration: step.Duration},
+			LatencyP50:        optionalDuration(step.LatencyP50),
+			LatencyP99:        optionalDuration(step.LatencyP99),
+		}
+		for _, req := range step.Requests {
+			r := v1alpha1.WarmupRunRequest{
+				Name:              req.Name,
+				RequestsCompleted: int32(req.RequestsCompleted),
+				RequestsFailed:    int32(req.RequestsFailed),
+				LatencyP50:        optionalDuration(req.LatencyP50),
+				LatencyP99:        optionalDuration(req.LatencyP99),
+				FirstLatency:      optionalDuration(req.FirstLatency),
+				Errors:            req.Errors,
+			}
+			if len(req.StatusCodes) > 0 {
+				r.StatusCodes = make(map[string]int32, len(req.StatusCodes))
+			}
+			for code, count := range req.StatusCodes {
+				r.StatusCodes[code] = int32(count)
+			}
+			s.Requests = append(s.Requests, r)
+		}
+		run.Status.Steps = append(run.Status.Steps, s)
+	}
+	return run
+}
+
+// optionalDuration returns d, or nil if it was not measured.
+func optionalDuration(d time.Duration) *metav1.Duration {
+	if d <= 0 {
+		return nil
+	}
+	return &metav1.Duration{Duration: d}
+}
+
+// recordWarmupRun creates run. Failures are logged; the warmup history is only
+// informational.
+func (r *PodReconciler) recordWarmupRun(ctx context.Context, run *v1alpha1.WarmupRun) {
+	logger := log.FromContext(ctx)
+	if err := r.Create(ctx, run); err != nil {
+		logger.Error(err, "failed to record warmup run")
+		return
+	}
+	logger.V(1).Info("recorded warmup run", "warmupRun", run.Name)
+}
+
+// WarmupRunCleaner deletes retained WarmupRuns whose spec.retainUntil has passed.
+// Runs without retention are owned by their pod and deleted with it by the garbage
+// collector.
+//
+// It implements manager.Runnable and is meant to run in the webhook Deployment, so
+// that a single instance does the cleanup.
+type WarmupRunCleaner struct {
+	Client   client.Client
+	Interval time.Duration
+}
+
+// Start implements manager.Runnable. It deletes expired runs every Interval until
+// ctx is done.
+func (c *WarmupRunCleaner) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("warmuprun-cleaner")
+	logger.Info("starting WarmupRun cleaner", "interval", c.Interval)
+
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			c.sweep(log.IntoContext(ctx, logger), time.Now())
+		}
+	}
+}
+
+// sweep deletes every run that expired before now and returns how many were deleted.
+func (c *WarmupRunCleaner) sweep(ctx context.Context, now time.Time) int {
+	logger := log.FromContext(ctx)
+
+	runs := &v1alpha1.WarmupRunList{}
+	if err := c.Client.List(ctx, runs); err != nil {
+		logger.Error(err, "unable to list WarmupRuns")
+		return 0
+	}
+
+	deleted := 0
+	for i := range runs.Items {
+		run := &runs.Items[i]
+		if run.Spec.RetainUntil == nil || !run.Spec.RetainUntil.Time.Before(now) {
+			continue
+		}
+		if err := c.Client.Delete(ctx, run); err != nil {
+			if !errors.IsNotFound(err) {
+				logger.Error(err, "failed to delete expired WarmupRun", "warmupRun", run.Name, "namespace", run.Namespace)
+			}
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		logger.Info("deleted expired WarmupRuns", "count", deleted)
+	}
+	return deleted
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestNewWarmupRun(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(3 * time.Second)
+	pod := makeReadyPod("checkout-7d9f", "shop", nil)
+	pod.UID = types.UID("uid-1")
+	pod.Labels = map[string]string{"app": "checkout"}
+	pod.Spec.NodeName = "node-1"
+
+	config := &warmup.Config{
+		WarmupConfigKind: webhook.KindWarmupConfig,
+		WarmupConfigName: "catalog",
+		Timeout:          30 * time.Second,
+	}
+	result := &warmup.Result{
+		Success:           false,
+		Message:           "warmup completed with failures",
+		Error:             errors.New("step browse failed"),
+		RequestsCompleted: 2,
+		RequestsFailed:    1,
+		TotalDuration:     2 * time.Second,
+		LatencyP50:        10 * time.Millisecond,
+		// Single response: no final window
+		FirstRequestLatency: 40 * time.Millisecond,
+		Steps: []warmup.StepResult{{
+			Name:              "browse",
+			RequestsCompleted: 2,
+			RequestsFailed:    1,
+			Duration:          time.Second,
+			Requests: []warmup.RequestResult{
+				{Name: "list", RequestsCompleted: 2, FirstLatency: 40 * time.Millisecond, StatusCodes: map[string]int{"200": 2}},
+				{Name: "detail", RequestsFailed: 1, Errors: []string{"connection refused"}},
+			},
+		}},
+	}
+
+	t.Run("owned by pod", func(t *testing.T) {
+		run := newWarmupRun(pod, 2, config, result, start, end, 0)
+
+		if run.GenerateName != "checkout-7d9f-2-" || run.Namespace != "shop" {
+			t.Errorf("GenerateName, Namespace = %q, %q", run.GenerateName, run.Namespace)
+		}
+		if run.Labels["app"] != "checkout" || run.Labels[webhook.LabelWarmupRunPodUID] != "uid-1" {
+			t.Errorf("Labels = %v, want the pod's labels and its UID", run.Labels)
+		}
+		if len(run.OwnerReferences) != 1 || run.OwnerReferences[0].UID != pod.UID || run.Spec.RetainUntil != nil {
+			t.Errorf("OwnerReferences = %+v, RetainUntil = %v; want owned by the pod", run.OwnerReferences, run.Spec.RetainUntil)
+		}
+		if run.Spec.PodName != pod.Name || run.Spec.NodeName != "node-1" || run.Spec.Attempt != 2 {
+			t.Errorf("Spec = %+v", run.Spec)
+		}
+		if run.Spec.Source.Kind != webhook.KindWarmupConfig || run.Spec.Source.Name != "catalog" ||
+			!strings.Contains(run.Spec.Source.Settings, "warmup-timeout=30s") {
+			t.Errorf("Source = %+v", run.Spec.Source)
+		}
+		status := run.Status
+		if !status.StartTime.Time.Equal(start) || !status.EndTime.Time.Equal(end) || status.Duration.Duration != 2*time.Second {
+			t.Errorf("times = %v, %v, %v", status.StartTime, status.EndTime, status.Duration)
+		}
+		if status.Success || status.Error != "step browse failed" || status.RequestsCompleted != 2 || status.RequestsFailed != 1 {
+			t.Errorf("Status = %+v", status)
+		}
+		if status.LatencyP50 == nil || status.LatencyP50.Duration != 10*time.Millisecond || status.LatencyP99 != nil {
+			t.Errorf("LatencyP50, LatencyP99 = %v, %v; want 10ms and unset", status.LatencyP50, status.LatencyP99)
+		}
+		if status.FirstRequestLatency == nil || status.FirstRequestLatency.Duration != 40*time.Millisecond || status.FinalWindowLatency != nil {
+			t.Errorf("FirstRequestLatency, FinalWindowLatency = %v, %v; want 40ms and unset", status.FirstRequestLatency, status.FinalWindowLatency)
+		}
+		if len(status.Steps) != 1 || len(status.Steps[0].Requests) != 2 {
+			t.Fatalf("Steps = %+v, want one step with two requests", status.Steps)
+		}
+		list, detail := status.Steps[0].Requests[0], status.Steps[0].Requests[1]
+		if list.FirstLatency == nil || list.FirstLatency.Duration != 40*time.Millisecond || list.StatusCodes["200"] != 2 {
+			t.Errorf("request list = %+v, want first latency 40ms and two 200s", list)
+		}
+		if detail.RequestsFailed != 1 || detail.StatusCodes != nil || len(detail.Errors) != 1 {
+			t.Errorf("request detail = %+v, want one failure with its error", detail)
+		}
+	})
+
+	t.Run("retained", func(t *testing.T) {
+		run := newWarmupRun(pod, 1, nil, &warmup.Result{Message: "config error"}, start, end, time.Hour)
+
+		if len(run.OwnerReferences) != 0 {
+			t.Errorf("OwnerReferences = %+v, want none", run.OwnerReferences)
+		}
+		if run.Spec.RetainUntil == nil || !run.Spec.RetainUntil.Time.Equal(end.Add(time.Hour)) {
+			t.Errorf("RetainUntil = %v, want %v", run.Spec.RetainUntil, end.Add(time.Hour))
+		}
+		if run.Spec.Source != (v1alpha1.WarmupRunSource{Kind: WarmupRunSourceAnnotations}) {
+			t.Errorf("Source = %+v, want Annotations without settings", run.Spec.Source)
+		}
+	})
+
+	t.Run("long pod name", func(t *testing.T) {
+		long := pod.DeepCopy()
+		long.Name = strings.Repeat("a", 253)
+		run := newWarmupRun(long, 12, nil, result, start, end, 0)
+		if len(run.GenerateName) != maxWarmupRunNamePrefix || !strings.HasSuffix(run.GenerateName, "-12-") {
+			t.Errorf("GenerateName = %q (%d), want %d characters ending in -12-", run.GenerateName, len(run.GenerateName), maxWarmupRunNamePrefix)
+		}
+	})
+}
+
+func TestPodReconciler_RecordsWarmupRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	tests := []struct {
+		name       string
+		annotation string
+		record     bool
+		wantRuns   int
+		wantError  bool
+	}{
+		{name: "disabled", record: false},
+		{name: "completed warmup", record: true, wantRuns: 1},
+		{name: "config error", annotation: "abc", record: true, wantRuns: 1, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := makeReadyPod("test-pod", "default", nil)
+			pod.Labels = map[string]string{"app": "checkout"}
+			if tt.annotation != "" {
+				pod.Annotations[webhook.AnnotationWarmupPort] = tt.annotation
+			}
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod).WithStatusSubresource(pod).Build()
+			r := &PodReconciler{
+				Client:           c,
+				Scheme:           scheme,
+				WarmupExecutor:   &warmup.MockExecutor{Result: &warmup.Result{Success: true, Message: "ok", RequestsCompleted: 3}},
+				Recorder:         events.NewFakeRecorder(100),
+				RecordWarmupRuns: tt.record,
+			}
+
+			if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+
+			runs := &v1alpha1.WarmupRunList{}
+			if err := c.List(context.Background(), runs, client.MatchingLabels{"app": "checkout"}); err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(runs.Items) != tt.wantRuns {
+				t.Fatalf("got %d WarmupRuns, want %d", len(runs.Items), tt.wantRuns)
+			}
+			if tt.wantRuns == 0 {
+				return
+			}
+			run := runs.Items[0]
+			if run.Spec.PodName != "test-pod" || run.Spec.Attempt != 1 {
+				t.Errorf("Spec = %+v", run.Spec)
+			}
+			if gotError := run.Status.Error != ""; gotError != tt.wantError || run.Status.Success == tt.wantError {
+				t.Errorf("Status = %+v, want error %v", run.Status, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestWarmupRunCleaner_Sweep(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	now := time.Now()
+	run := func(name string, retainUntil *time.Time) *v1alpha1.WarmupRun {
+		r := &v1alpha1.WarmupRun{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+		if retainUntil != nil {
+			until := metav1.NewTime(*retainUntil)
+			r.Spec.RetainUntil = &until
+		}
+		return r
+	}
+	expired := now.Add(-time.Minute)
+	retained := now.Add(time.Minute)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		run("expired", &expired),
+		run("retained", &retained),
+		run("owned", nil),
+	).Build()
+	cleaner := &WarmupRunCleaner{Client: c, Interval: time.Minute}
+
+	if got := cleaner.sweep(context.Background(), now); got != 1 {
+		t.Errorf("sweep() = %d, want 1", got)
+	}
+	runs := &v1alpha1.WarmupRunList{}
+	if err := c.List(context.Background(), runs); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var names []string
+	for _, r := range runs.Items {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "owned,retained" {
+		t.Errorf("remaining runs = %v, want [owned retained]", names)
+	}
+}
@@ -0,0 +1,427 @@
+package controller
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+const (
+	// statusWindow is the number of most recent warmup runs of current pods the
+	// WarmupConfig statistics are computed from.
+	statusWindow = 50
+
+	// maxConditionMessage caps the length of the Valid condition message.
+	maxConditionMessage = 1024
+)
+
+// WarmupConfigStatusReconciler maintains the status of WarmupConfig objects: the
+// pods that use each config, statistics of the warmup runs of those current pods,
+// and a Valid condition with the result of the validating webhook's checks.
+//
+// Warmups run in the node-local controllers, which record the outcome of each run
+// in the pod's kube-booster.io/warmup-result annotation (see warmup.RunSummary).
+// This reconciler aggregates those annotations, so that the status has a single
+// writer; runs of deleted pods drop out of the statistics. It is meant to run in
+// the webhook Deployment.
+type WarmupConfigStatusReconciler struct {
+	client.Client
+}
+
+// Reconcile recomputes the status of a WarmupConfig
+func (r *WarmupConfigStatusReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	cfg := &v1alpha1.WarmupConfig{}
+	if err := r.Get(ctx, req.NamespacedName, cfg); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(cfg.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	usage := r.newWarmupConfigUsage(ctx, cfg.Namespace)
+	uses := func(pod *corev1.Pod) bool {
+		return slices.Contains(usage.configs(ctx, pod), cfg.Name)
+	}
+	status := computeWarmupConfigStatus(ctx, cfg, pods.Items, uses)
+	if equality.Semantic.DeepEqual(cfg.Status, *status) {
+		return ctrl.Result{}, nil
+	}
+	cfg.Status = *status
+	if err := r.Status().Update(ctx, cfg); err != nil {
+		// A conflict is retried with the updated object
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// computeWarmupConfigStatus returns the status of cfg given the pods in its
+// namespace. uses reports whether a pod's warmup uses cfg, after its inherited
+// settings and includes are resolved. Unreadable result annotations are logged and
+// skipped.
+func computeWarmupConfigStatus(ctx context.Context, cfg *v1alpha1.WarmupConfig, pods []corev1.Pod, uses func(pod *corev1.Pod) bool) *v1alpha1.WarmupConfigStatus {
+	logger := log.FromContext(ctx)
+	status := cfg.Status.DeepCopy()
+	status.ObservedGeneration = cfg.Generation
+
+	var runs []*warmup.RunSummary
+	status.Pods = 0
+	for i := range pods {
+		pod := &pods[i]
+		summary, err := warmup.ParseRunSummary(pod)
+		if err != nil {
+			logger.V(1).Info("ignoring warmup result", "pod", pod.Name, "error", err.Error())
+		}
+		ran := summary != nil && referencesWarmupConfig(summary.Config, cfg.Name)
+		if ran {
+			runs = append(runs, summary)
+		}
+		// A pod that ran with the config still counts if its settings changed since
+		if ran || uses(pod) {
+			status.Pods++
+		}
+	}
+
+	// Newest first; the window keeps the most recent runs
+	sort.SliceStable(runs, func(i, j int) bool { return runs[j].Time.Before(&runs[i].Time) })
+	if len(runs) > statusWindow {
+		runs = runs[:statusWindow]
+	}
+
+	status.CurrentPodRuns = int32(len(runs))
+	status.SuccessRate = nil
+	status.MeanDuration = nil
+	status.StepFailures = nil
+	if len(runs) > 0 {
+		// Pods come and go, so the last execution time is kept even when the pod
+		// that ran it has been deleted
+		if last := runs[0].Time; status.LastExecutionTime == nil || status.LastExecutionTime.Before(&last) {
+			status.LastExecutionTime = &last
+		}
+
+		succeeded := 0
+		var total int64
+		failures := map[string]int32{}
+		var order []string
+		for _, run := range runs {
+			if run.Success {
+				succeeded++
+			}
+			total += run.DurationMillis
+			for _, step := range run.FailedSteps {
+				if failures[step] == 0 {
+					order = append(order, step)
+				}
+				failures[step]++
+			}
+		}
+		rate := int32(math.Round(float64(succeeded) * 100 / float64(len(runs))))
+		status.SuccessRate = &rate
+		status.MeanDuration = &metav1.Duration{Duration: time.Duration(total/int64(len(runs))) * time.Millisecond}
+		for _, step := range order {
+			status.StepFailures = append(status.StepFailures, v1alpha1.StepFailureCount{Step: step, Failures: failures[step]})
+		}
+	}
+
+	meta.SetStatusCondition(&status.Conditions, validCondition(cfg))
+	return status
+}
+
+// validCondition runs the validating webhook's checks on cfg's spec, which catches
+// configs that were created while the webhook was unavailable.
+func validCondition(cfg *v1alpha1.WarmupConfig) metav1.Condition {
+	errs, warnings := warmup.ValidateWarmupConfigSpec(&cfg.Spec, field.NewPath("spec"))
+	condition := metav1.Condition{
+		Type:               v1alpha1.WarmupConfigConditionValid,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: cfg.Generation,
+		Reason:             "Valid",
+		Message:            strings.Join(warnings, "; "),
+	}
+	if len(errs) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "Invalid"
+		condition.Message = errs.ToAggregate().Error()
+	}
+	if len(condition.Message) > maxConditionMessage {
+		condition.Message = condition.Message[:maxConditionMessage-3] + "..."
+	}
+	return condition
+}
+
+// referencesWarmupConfig reports whether ref, a warmup-config reference in
+// annotation form, names the WarmupConfig name in the pod's namespace.
+func referencesWarmupConfig(ref, name string) bool {
+	if ref == "" {
+		return false
+	}
+	kind, refName, err := warmup.ParseWarmupConfigRef(ref)
+	return err == nil && kind == webhook.KindWarmupConfig && refName == name
+}
+
+// warmupConfigUsage resolves the WarmupConfigs that the pods of a namespace use.
+// Referenced configs are expanded once per reconcile.
+type warmupConfigUsage struct {
+	client.Reader
+	namespace string
+	ns        *corev1.Namespace
+	expanded  map[string][]string
+}
+
+// newWarmupConfigUsage returns a warmupConfigUsage for namespace. A namespace that
+// cannot be read provides no defaults.
+func (r *WarmupConfigStatusReconciler) newWarmupConfigUsage(ctx context.Context, namespace string) *warmupConfigUsage {
+	u := &warmupConfigUsage{Reader: r.Client, namespace: namespace, expanded: map[string][]string{}}
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, ns); err == nil {
+		u.ns = ns
+	} else if !errors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "failed to get namespace, ignoring namespace warmup settings")
+	}
+	return u
+}
+
+// configs returns the names of the WarmupConfigs the pod's warmup uses: the config
+// its effective warmup-config setting references, taken from the pod, its policy or
+// its namespace, and the WarmupConfigs that config includes or takes steps from. It
+// returns nil if warmup is not enabled for the pod.
+func (u *warmupConfigUsage) configs(ctx context.Context, pod *corev1.Pod) []string {
+	d := webhook.Defaults{Namespace: u.ns}
+	policy, err := webhook.ResolvePolicy(ctx, u.Reader, pod, u.namespace, u.ns)
+	if err != nil {
+		log.FromContext(ctx).V(1).Info("ignoring warmup policies", "pod", pod.Name, "error", err.Error())
+	}
+	d.Policy = policy
+	if enabled, _ := d.WarmupEnabled(pod); !enabled {
+		return nil
+	}
+	annotations, _ := d.EffectiveAnnotations(pod)
+	ref := annotations[webhook.AnnotationWarmupConfig]
+	if ref == "" {
+		return nil
+	}
+	if names, ok := u.expanded[ref]; ok {
+		return names
+	}
+	kind, name, err := warmup.ParseWarmupConfigRef(ref)
+	if err != nil {
+		return nil
+	}
+	var names []string
+	u.expand(ctx, v1alpha1.WarmupConfigReference{Kind: kind, Name: name}, 0, &names)
+	u.expanded[ref] = names
+	return names
+}
+
+// expand appends to names the WarmupConfig ref names, if it is one, and the
+// WarmupConfigs it includes or takes steps from, following ClusterWarmupConfigs that
+// allow the namespace too. Configs that do not exist, and references nested deeper
+// than the warmup itself would follow, are skipped.
+func (u *warmupConfigUsage) expand(ctx context.Context, ref v1alpha1.WarmupConfigReference, depth int, names *[]string) {
+	if depth > maxIncludeDepth {
+		return
+	}
+	var spec *v1alpha1.WarmupConfigSpec
+	if ref.Kind == webhook.KindClusterWarmupConfig {
+		clusterCfg := &v1alpha1.ClusterWarmupConfig{}
+		if err := u.Get(ctx, types.NamespacedName{Name: ref.Name}, clusterCfg); err != nil ||
+			!clusterWarmupConfigAllows(clusterCfg, u.namespace, u.ns) {
+			return
+		}
+		spec = &clusterCfg.Spec.WarmupConfigSpec
+	} else {
+		if slices.Contains(*names, ref.Name) {
+			// Already expanded, or an include cycle
+			return
+		}
+		warmupCfg := &v1alpha1.WarmupConfig{}
+		if err := u.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: u.namespace}, warmupCfg); err != nil {
+			return
+		}
+		*names = append(*names, ref.Name)
+		spec = &warmupCfg.Spec
+	}
+
+	for _, inc := range spec.Includes {
+		u.expand(ctx, inc, depth+1, names)
+	}
+	for _, step := range spec.Steps {
+		if step.StepsFrom != nil {
+			u.expand(ctx, *step.StepsFrom, depth+1, names)
+		}
+	}
+}
+
+// warmupConfigsForPod maps a pod to the WarmupConfigs its warmup uses and the one
+// its recorded warmup result names.
+func (r *WarmupConfigStatusReconciler) warmupConfigsForPod(ctx context.Context, obj client.Object) []reconcile.Request {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil
+	}
+	names := r.newWarmupConfigUsage(ctx, pod.Namespace).configs(ctx, pod)
+	if summary, err := warmup.ParseRunSummary(pod); err == nil && summary != nil {
+		if kind, name, err := warmup.ParseWarmupConfigRef(summary.Config); err == nil && kind == webhook.KindWarmupConfig &&
+			!slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	var requests []reconcile.Request
+	for _, name := range names {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: pod.Namespace, Name: name}})
+	}
+	return requests
+}
+
+// warmupConfigsInNamespace maps a WarmupConfig, WarmupPolicy or Namespace to all
+// WarmupConfigs in its namespace, whose pods may have changed which configs they
+// use.
+func (r *WarmupConfigStatusReconciler) warmupConfigsInNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	namespace := obj.GetNamespace()
+	if _, ok := obj.(*corev1.Namespace); ok {
+		namespace = obj.GetName()
+	}
+	configs := &v1alpha1.WarmupConfigList{}
+	if err := r.List(ctx, configs, client.InNamespace(namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "failed to list WarmupConfigs", "namespace", namespace)
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range configs.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&configs.Items[i])})
+	}
+	return requests
+}
+
+// clusterObjectHandler enqueues the WarmupConfigs of the namespaces a cluster-scoped
+// policy or config applies to, as listed by namespaces, both before and after a
+// change, since pods in either set may have changed which configs they use.
+func (r *WarmupConfigStatusReconciler) clusterObjectHandler(namespaces func(obj client.Object, all []corev1.Namespace) []string) handler.EventHandler {
+	enqueue := func(ctx context.Context, q workqueue.TypedRateLimitingInterface[reconcile.Request], objs ...client.Object) {
+		logger := log.FromContext(ctx)
+		all := &corev1.NamespaceList{}
+		if err := r.List(ctx, all); err != nil {
+			logger.Error(err, "failed to list namespaces")
+			return
+		}
+		affected := map[string]bool{}
+		for _, obj := range objs {
+			for _, name := range namespaces(obj, all.Items) {
+				affected[name] = true
+			}
+		}
+		if len(affected) == 0 {
+			return
+		}
+		configs := &v1alpha1.WarmupConfigList{}
+		if err := r.List(ctx, configs); err != nil {
+			logger.Error(err, "failed to list WarmupConfigs")
+			return
+		}
+		for i := range configs.Items {
+			if affected[configs.Items[i].Namespace] {
+				q.Add(reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&configs.Items[i])})
+			}
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(ctx context.Context, e event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		UpdateFunc: func(ctx context.Context, e event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.ObjectOld, e.ObjectNew)
+		},
+		DeleteFunc: func(ctx context.Context, e event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+		GenericFunc: func(ctx context.Context, e event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(ctx, q, e.Object)
+		},
+	}
+}
+
+// clusterPolicyNamespaces returns the namespaces whose pods a ClusterWarmupPolicy
+// may select: those matching its namespace selector, or all of them if it has none.
+func clusterPolicyNamespaces(obj client.Object, all []corev1.Namespace) []string {
+	policy, ok := obj.(*v1alpha1.ClusterWarmupPolicy)
+	if !ok {
+		return nil
+	}
+	var selector labels.Selector = labels.Everything()
+	if policy.Spec.NamespaceSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector); err != nil {
+			// Invalid selectors select nothing
+			return nil
+		}
+	}
+	var names []string
+	for i := range all {
+		if selector.Matches(labels.Set(all[i].Labels)) {
+			names = append(names, all[i].Name)
+		}
+	}
+	return names
+}
+
+// clusterConfigNamespaces returns the namespaces a ClusterWarmupConfig allows.
+func clusterConfigNamespaces(obj client.Object, all []corev1.Namespace) []string {
+	cfg, ok := obj.(*v1alpha1.ClusterWarmupConfig)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for i := range all {
+		if clusterWarmupConfigAllows(cfg, all[i].Name, &all[i]) {
+			names = append(names, all[i].Name)
+		}
+	}
+	return names
+}
+
+// SetupWithManager sets up the controller with the Manager
+func (r *WarmupConfigStatusReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("warmupconfig-status").
+		// Status updates do not change the generation, so they do not trigger another reconcile
+		For(&v1alpha1.WarmupConfig{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.warmupConfigsForPod)).
+		// Changed includes, policies and namespace defaults change which configs pods use
+		Watches(&v1alpha1.WarmupConfig{}, handler.EnqueueRequestsFromMapFunc(r.warmupConfigsInNamespace),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1alpha1.WarmupPolicy{}, handler.EnqueueRequestsFromMapFunc(r.warmupConfigsInNamespace)).
+		Watches(&v1alpha1.ClusterWarmupPolicy{}, r.clusterObjectHandler(clusterPolicyNamespaces),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&v1alpha1.ClusterWarmupConfig{}, r.clusterObjectHandler(clusterConfigNamespaces),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.warmupConfigsInNamespace),
+			builder.WithPredicates(predicate.Or(predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
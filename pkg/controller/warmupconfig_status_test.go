@@ -0,0 +1,353 @@
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/warmup"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+// podWithRun returns a pod in namespace default that enables warmup with the
+// warmup-config annotation ref, if ref is set, and, if summary is not nil, whose
+// recorded warmup result is summary.
+func podWithRun(t *testing.T, name, ref string, summary *warmup.RunSummary) *corev1.Pod {
+	t.Helper()
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Annotations: map[string]string{}}}
+	if ref != "" {
+		pod.Annotations[webhook.AnnotationWarmupEnabled] = webhook.WarmupEnabledValue
+		pod.Annotations[webhook.AnnotationWarmupConfig] = ref
+	}
+	if summary != nil {
+		value, err := summary.Annotation()
+		if err != nil {
+			t.Fatalf("Annotation() error = %v", err)
+		}
+		pod.Annotations[webhook.AnnotationWarmupResult] = value
+	}
+	return pod
+}
+
+var (
+	// checkoutConfig is a WarmupConfig in namespace default that includes catalog
+	checkoutConfig = &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "checkout", Namespace: "default"},
+		Spec:       v1alpha1.WarmupConfigSpec{Includes: []v1alpha1.WarmupConfigReference{{Name: "catalog"}}},
+	}
+
+	// shopPolicy enables warmup with WarmupConfig catalog for app=shop pods in
+	// namespace default
+	shopPolicy = &v1alpha1.WarmupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: "default"},
+		Spec: v1alpha1.WarmupPolicySpec{
+			PodSelector:     metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+			WarmupConfigRef: &v1alpha1.WarmupConfigReference{Name: "catalog"},
+		},
+	}
+)
+
+// shopPod returns a pod in namespace default selected by shopPolicy.
+func shopPod(name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: map[string]string{"app": "shop"}}}
+}
+
+func TestWarmupConfigStatusReconciler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	run := func(minute int, success bool, durationMillis int64, failedSteps ...string) *warmup.RunSummary {
+		return &warmup.RunSummary{
+			Config:         "catalog",
+			Time:           metav1.NewTime(start.Add(time.Duration(minute) * time.Minute)),
+			Success:        success,
+			DurationMillis: durationMillis,
+			FailedSteps:    failedSteps,
+		}
+	}
+
+	cfg := &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default", Generation: 3},
+		Spec:       v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: "browse"}}},
+	}
+	invalid := &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+		Spec:       v1alpha1.WarmupConfigSpec{Timeout: "2 minutes", Steps: []v1alpha1.WarmupStep{{Name: "browse"}}},
+	}
+	disabled := podWithRun(t, "j", "catalog", nil)
+	disabled.Annotations[webhook.AnnotationWarmupEnabled] = "false"
+	objs := []client.Object{
+		cfg, invalid, checkoutConfig, shopPolicy,
+		podWithRun(t, "a", "catalog", run(1, true, 1000)),
+		podWithRun(t, "b", "catalog", run(3, false, 4000, "login", "browse")),
+		podWithRun(t, "c", "catalog", run(2, true, 1000, "browse")),
+		// Not run yet
+		podWithRun(t, "d", "catalog", nil),
+		// warmup-config inherited from a policy
+		podWithRun(t, "e", "", run(0, true, 2000)),
+		// Other configs
+		podWithRun(t, "f", "ClusterWarmupConfig/catalog", nil),
+		podWithRun(t, "g", "search", &warmup.RunSummary{Config: "search", Success: true}),
+		// warmup-config from a policy, not run yet
+		shopPod("h"),
+		// A config that includes catalog
+		podWithRun(t, "i", "checkout", nil),
+		disabled,
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(cfg, invalid).
+		Build()
+	r := &WarmupConfigStatusReconciler{Client: c}
+
+	for _, name := range []string{"catalog", "broken"} {
+		if _, err := r.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: name, Namespace: "default"},
+		}); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", name, err)
+		}
+	}
+
+	got := &v1alpha1.WarmupConfig{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(cfg), got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	status := got.Status
+	if status.Pods != 7 {
+		t.Errorf("Pods = %d, want 7", status.Pods)
+	}
+	if status.CurrentPodRuns != 4 {
+		t.Errorf("CurrentPodRuns = %d, want 4", status.CurrentPodRuns)
+	}
+	if status.SuccessRate == nil || *status.SuccessRate != 75 {
+		t.Errorf("SuccessRate = %v, want 75", status.SuccessRate)
+	}
+	if status.MeanDuration == nil || status.MeanDuration.Duration != 2*time.Second {
+		t.Errorf("MeanDuration = %v, want 2s", status.MeanDuration)
+	}
+	if status.LastExecutionTime == nil || !status.LastExecutionTime.Time.Equal(start.Add(3*time.Minute)) {
+		t.Errorf("LastExecutionTime = %v, want %v", status.LastExecutionTime, start.Add(3*time.Minute))
+	}
+	wantFailures := []v1alpha1.StepFailureCount{{Step: "login", Failures: 1}, {Step: "browse", Failures: 2}}
+	if !slices.Equal(status.StepFailures, wantFailures) {
+		t.Errorf("StepFailures = %+v, want %+v", status.StepFailures, wantFailures)
+	}
+	if status.ObservedGeneration != 3 {
+		t.Errorf("ObservedGeneration = %d, want 3", status.ObservedGeneration)
+	}
+	if !meta.IsStatusConditionTrue(status.Conditions, v1alpha1.WarmupConfigConditionValid) {
+		t.Errorf("Valid condition = %+v, want True", status.Conditions)
+	}
+
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(invalid), got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	valid := meta.FindStatusCondition(got.Status.Conditions, v1alpha1.WarmupConfigConditionValid)
+	if valid == nil || valid.Status != metav1.ConditionFalse || !strings.Contains(valid.Message, "spec.timeout") {
+		t.Errorf("Valid condition = %+v, want False naming spec.timeout", valid)
+	}
+	if got.Status.Pods != 0 || got.Status.SuccessRate != nil || got.Status.MeanDuration != nil {
+		t.Errorf("status of an unused config = %+v, want no pods and no statistics", got.Status)
+	}
+}
+
+func TestWarmupConfigStatusReconciler_KeepsLastExecutionTime(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(earlier.Add(time.Hour))
+	cfg := &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default"},
+		Status:     v1alpha1.WarmupConfigStatus{LastExecutionTime: &later, CurrentPodRuns: 3},
+	}
+	pods := []corev1.Pod{*podWithRun(t, "a", "catalog", &warmup.RunSummary{Config: "catalog", Time: earlier})}
+
+	status := computeWarmupConfigStatus(context.Background(), cfg, pods, func(*corev1.Pod) bool { return true })
+	if !status.LastExecutionTime.Equal(&later) {
+		t.Errorf("LastExecutionTime = %v, want %v (the pod that ran last was deleted)", status.LastExecutionTime, later)
+	}
+	if status.CurrentPodRuns != 1 {
+		t.Errorf("CurrentPodRuns = %d, want 1", status.CurrentPodRuns)
+	}
+}
+
+func TestWarmupConfigsForPod(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+	catalog := &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default"}}
+	search := &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "default"}}
+	r := &WarmupConfigStatusReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(catalog, search, checkoutConfig, shopPolicy).Build(),
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want []string
+	}{
+		{name: "no reference", pod: podWithRun(t, "a", "", nil)},
+		{name: "annotation", pod: podWithRun(t, "a", "catalog", nil), want: []string{"catalog"}},
+		{name: "annotation and result", pod: podWithRun(t, "a", "catalog", &warmup.RunSummary{Config: "catalog"}), want: []string{"catalog"}},
+		{name: "reference changed", pod: podWithRun(t, "a", "search", &warmup.RunSummary{Config: "catalog"}), want: []string{"search", "catalog"}},
+		{name: "inherited reference", pod: podWithRun(t, "a", "", &warmup.RunSummary{Config: "catalog"}), want: []string{"catalog"}},
+		{name: "reference from policy", pod: shopPod("a"), want: []string{"catalog"}},
+		{name: "included config", pod: podWithRun(t, "a", "checkout", nil), want: []string{"checkout", "catalog"}},
+		{name: "missing config", pod: podWithRun(t, "a", "orders", nil)},
+		{name: "ClusterWarmupConfig", pod: podWithRun(t, "a", "ClusterWarmupConfig/catalog", nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, req := range r.warmupConfigsForPod(context.Background(), tt.pod) {
+				if req.Namespace != "default" {
+					t.Errorf("request %v, want namespace default", req)
+				}
+				got = append(got, req.Name)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("warmupConfigsForPod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := r.warmupConfigsForPod(context.Background(), &corev1.Namespace{}); got != nil {
+		t.Errorf("warmupConfigsForPod(Namespace) = %v, want nil", got)
+	}
+}
+
+func TestWarmupConfigStatusReconciler_ClusterWarmupPolicyChange(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	teamA := &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "team-a"}}
+	teamB := &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "team-b"}}
+	policy := &v1alpha1.ClusterWarmupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "shop"},
+		Spec: v1alpha1.WarmupPolicySpec{
+			NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "web"}},
+			PodSelector:       metav1.LabelSelector{MatchLabels: map[string]string{"app": "shop"}},
+			WarmupConfigRef:   &v1alpha1.WarmupConfigReference{Name: "catalog"},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"tier": "web"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+			teamA, teamB, policy,
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "team-a", Labels: map[string]string{"app": "shop"}}},
+		).
+		WithStatusSubresource(teamA, teamB).
+		Build()
+	r := &WarmupConfigStatusReconciler{Client: c}
+	ctx := context.Background()
+
+	pods := func() int32 {
+		t.Helper()
+		got := &v1alpha1.WarmupConfig{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(teamA), got); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return got.Status.Pods
+	}
+	if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(teamA)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if got := pods(); got != 1 {
+		t.Fatalf("Pods = %d before the policy change, want 1", got)
+	}
+
+	// The policy no longer selects team-a
+	updated := policy.DeepCopy()
+	updated.Spec.NamespaceSelector = &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "api"}}
+	if err := c.Update(ctx, updated); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	r.clusterObjectHandler(clusterPolicyNamespaces).Update(ctx, event.UpdateEvent{ObjectOld: policy, ObjectNew: updated}, q)
+	var enqueued []string
+	for q.Len() > 0 {
+		req, _ := q.Get()
+		q.Done(req)
+		enqueued = append(enqueued, req.String())
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile(%s) error = %v", req, err)
+		}
+	}
+	if !slices.Equal(enqueued, []string{"team-a/catalog"}) {
+		t.Errorf("enqueued %v, want [team-a/catalog]", enqueued)
+	}
+	if got := pods(); got != 0 {
+		t.Errorf("Pods = %d after the policy change, want 0", got)
+	}
+}
+
+func TestPodReconciler_RecordsRunSummary(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	pod := makeReadyPod("test-pod", "default", map[string]string{webhook.AnnotationWarmupConfig: "catalog"})
+	cfg := &v1alpha1.WarmupConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: "catalog", Namespace: "default"},
+		Spec:       v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: "login"}, {Name: "browse"}}},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pod, cfg).WithStatusSubresource(pod).Build()
+	r := &PodReconciler{
+		Client: c,
+		Scheme: scheme,
+		ScenarioExecutor: &warmup.MockScenarioExecutor{Result: &warmup.Result{
+			Success:           true,
+			RequestsCompleted: 3,
+			RequestsFailed:    1,
+			TotalDuration:     1500 * time.Millisecond,
+			Steps: []warmup.StepResult{
+				{Name: "login", RequestsCompleted: 1},
+				{Name: "browse", RequestsCompleted: 2, RequestsFailed: 1},
+			},
+		}},
+		Recorder: events.NewFakeRecorder(100),
+	}
+
+	if _, err := r.Reconcile(context.Background(), ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pod)}); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	updated := &corev1.Pod{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(pod), updated); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !r.isConditionTrue(updated, webhook.ConditionTypeWarmupReady) {
+		t.Error("warmup condition should be True")
+	}
+	summary, err := warmup.ParseRunSummary(updated)
+	if err != nil || summary == nil {
+		t.Fatalf("ParseRunSummary() = %v, %v; want the recorded result", summary, err)
+	}
+	if summary.Config != "catalog" || !summary.Success || summary.DurationMillis != 1500 ||
+		!slices.Equal(summary.FailedSteps, []string{"browse"}) || summary.Time.IsZero() {
+		t.Errorf("recorded result = %+v", summary)
+	}
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+const (
+	// ReasonWarmupWatchdogTimeout is the event and condition reason used when the
+	// watchdog releases a pod's readiness gate.
+	ReasonWarmupWatchdogTimeout = "WarmupWatchdogTimeout"
+
+	// DefaultWatchdogMaxAge is how long after ContainersReady a pod may wait for the
+	// warmup condition before the watchdog sets it. It leaves room for the maximum
+	// warmup timeout plus time spent waiting for a concurrency slot.
+	DefaultWatchdogMaxAge = 10 * time.Minute
+
+	// DefaultWatchdogInterval is how often the watchdog checks pods.
+	DefaultWatchdogInterval = time.Minute
+)
+
+// ReadinessWatchdog releases pods that are stuck behind the warmup readiness gate.
+//
+// A pod whose kube-booster.io/warmup-ready condition is still not True MaxAge after
+// its containers became ready is assumed to have been missed by the node-local
+// controller (not running, misconfigured, or not scheduled on the node). The
+// watchdog sets the condition True with reason WarmupWatchdogTimeout (fail-open),
+// emits a warning event, and counts the pod in
+// kube_booster_warmup_watchdog_timeouts_total.
+//
+// It implements manager.Runnable and is meant to run in the webhook Deployment,
+// independently of the controllers it is watching over.
+type ReadinessWatchdog struct {
+	Client   client.Client
+	Recorder events.EventRecorder
+	MaxAge   time.Duration
+	Interval time.Duration
+}
+
+// Start implements manager.Runnable. It checks pods every Interval until ctx is done.
+func (w *ReadinessWatchdog) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx).WithName("watchdog")
+	logger.Info("starting readiness watchdog", "maxAge", w.MaxAge, "interval", w.Interval)
+
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.sweep(log.IntoContext(ctx, logger), time.Now())
+		}
+	}
+}
+
+// sweep releases every stuck pod and returns how many were released.
+func (w *ReadinessWatchdog) sweep(ctx context.Context, now time.Time) int {
+	logger := log.FromContext(ctx)
+
+	pods := &corev1.PodList{}
+	if err := w.Client.List(ctx, pods); err != nil {
+		logger.Error(err, "unable to list pods")
+		return 0
+	}
+
+	released := 0
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		waited, stuck := w.stuck(pod, now)
+		if !stuck {
+			continue
+		}
+		message := fmt.Sprintf("Warmup condition not set within %s after containers became ready; "+
+			"pod marked ready (fail-open). Check that the kube-booster controller is running on node %s",
+			w.MaxAge, pod.Spec.NodeName)
+		if err := setWarmupConditionTrue(ctx, w.Client, pod, ReasonWarmupWatchdogTimeout, message); err != nil {
+			// A conflict means the pod changed (possibly the controller caught up);
+			// it is looked at again on the next sweep
+			if !errors.IsConflict(err) && !errors.IsNotFound(err) {
+				logger.Error(err, "failed to release readiness gate", "pod", pod.Name, "namespace", pod.Namespace)
+			}
+			continue
+		}
+		released++
+		logger.Info("released stuck readiness gate", "pod", pod.Name, "namespace", pod.Namespace,
+			"node", pod.Spec.NodeName, "waited", waited.Round(time.Second))
+		w.Recorder.Eventf(pod, nil, corev1.EventTypeWarning, ReasonWarmupWatchdogTimeout, "ReleaseReadinessGate",
+			"%s", message)
+		metrics.RecordWarmupWatchdogTimeout(pod.Namespace)
+	}
+	return released
+}
+
+// stuck reports whether pod has been waiting for the warmup condition for longer
+// than MaxAge since its containers became ready, and how long it has waited.
+func (w *ReadinessWatchdog) stuck(pod *corev1.Pod, now time.Time) (time.Duration, bool) {
+	if !hasReadinessGate(pod) || pod.DeletionTimestamp != nil || pod.Status.Phase != corev1.PodRunning {
+		return 0, false
+	}
+	var containersReady *corev1.PodCondition
+	for i, condition := range pod.Status.Conditions {
+		switch string(condition.Type) {
+		case webhook.ConditionTypeWarmupReady:
+			if condition.Status == corev1.ConditionTrue {
+				return 0, false
+			}
+		case string(corev1.ContainersReady):
+			containersReady = &pod.Status.Conditions[i]
+		}
+	}
+	if containersReady == nil || containersReady.Status != corev1.ConditionTrue {
+		return 0, false
+	}
+	waited := now.Sub(containersReady.LastTransitionTime.Time)
+	return waited, waited > w.MaxAge
+}
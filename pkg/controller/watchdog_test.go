@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/events"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestReadinessWatchdog_Sweep(t *testing.T) {
+	now := time.Now()
+	readySince := func(name string, ago time.Duration) *corev1.Pod {
+		pod := makeReadyPod(name, "default", nil)
+		pod.Spec.NodeName = "node-1"
+		pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(-ago))
+		return pod
+	}
+
+	tests := []struct {
+		name        string
+		pod         func() *corev1.Pod
+		wantRelease bool
+	}{
+		{
+			name:        "condition missing past max age",
+			pod:         func() *corev1.Pod { return readySince("stuck", 15*time.Minute) },
+			wantRelease: true,
+		},
+		{
+			name: "condition False past max age",
+			pod: func() *corev1.Pod {
+				pod := readySince("stuck-false", 15*time.Minute)
+				pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+					Type:   corev1.PodConditionType(webhook.ConditionTypeWarmupReady),
+					Status: corev1.ConditionFalse,
+				})
+				return pod
+			},
+			wantRelease: true,
+		},
+		{
+			name: "containers ready recently",
+			pod:  func() *corev1.Pod { return readySince("recent", time.Minute) },
+		},
+		{
+			name: "condition already True",
+			pod: func() *corev1.Pod {
+				pod := readySince("done", 15*time.Minute)
+				pod.Status.Conditions = append(pod.Status.Conditions, corev1.PodCondition{
+					Type:   corev1.PodConditionType(webhook.ConditionTypeWarmupReady),
+					Status: corev1.ConditionTrue,
+				})
+				return pod
+			},
+		},
+		{
+			name: "no readiness gate",
+			pod: func() *corev1.Pod {
+				pod := readySince("no-gate", 15*time.Minute)
+				pod.Spec.ReadinessGates = nil
+				return pod
+			},
+		},
+		{
+			name: "containers not ready",
+			pod: func() *corev1.Pod {
+				pod := readySince("not-ready", 15*time.Minute)
+				pod.Status.Conditions[0].Status = corev1.ConditionFalse
+				return pod
+			},
+		},
+		{
+			name: "not running",
+			pod: func() *corev1.Pod {
+				pod := readySince("pending", 15*time.Minute)
+				pod.Status.Phase = corev1.PodPending
+				return pod
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics.WarmupWatchdogTimeoutsTotal.Reset()
+
+			pod := tt.pod()
+			scheme := runtime.NewScheme()
+			_ = corev1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(pod).
+				WithStatusSubresource(pod).
+				Build()
+			recorder := events.NewFakeRecorder(10)
+			w := &ReadinessWatchdog{
+				Client:   fakeClient,
+				Recorder: recorder,
+				MaxAge:   DefaultWatchdogMaxAge,
+				Interval: DefaultWatchdogInterval,
+			}
+
+			released := w.sweep(context.Background(), now)
+			if want := map[bool]int{true: 1, false: 0}[tt.wantRelease]; released != want {
+				t.Errorf("sweep() released %d pods, want %d", released, want)
+			}
+
+			updated := &corev1.Pod{}
+			if err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(pod), updated); err != nil {
+				t.Fatalf("failed to get pod: %v", err)
+			}
+			var condition *corev1.PodCondition
+			for i := range updated.Status.Conditions {
+				if string(updated.Status.Conditions[i].Type) == webhook.ConditionTypeWarmupReady {
+					condition = &updated.Status.Conditions[i]
+				}
+			}
+			if !tt.wantRelease {
+				if condition != nil && condition.Reason == ReasonWarmupWatchdogTimeout {
+					t.Error("watchdog released a pod that was not stuck")
+				}
+				if len(recorder.Events) != 0 {
+					t.Errorf("unexpected event: %s", <-recorder.Events)
+				}
+				return
+			}
+
+			if condition == nil || condition.Status != corev1.ConditionTrue || condition.Reason != ReasonWarmupWatchdogTimeout {
+				t.Errorf("warmup condition = %+v, want True with reason %s", condition, ReasonWarmupWatchdogTimeout)
+			}
+			select {
+			case ev := <-recorder.Events:
+				if !strings.HasPrefix(ev, "Warning "+ReasonWarmupWatchdogTimeout) || !strings.Contains(ev, "node-1") {
+					t.Errorf("event = %q, want Warning %s naming the node", ev, ReasonWarmupWatchdogTimeout)
+				}
+			default:
+				t.Errorf("no %s event recorded", ReasonWarmupWatchdogTimeout)
+			}
+			if got := testutil.ToFloat64(metrics.WarmupWatchdogTimeoutsTotal.WithLabelValues("default")); got != 1 {
+				t.Errorf("watchdog_timeouts_total = %v, want 1", got)
+			}
+		})
+	}
+}
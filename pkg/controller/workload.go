@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+)
+
+const (
+	// podTemplateHashLabel is the label the Deployment controller sets on the pods of
+	// each of its ReplicaSets, whose names end with the same hash.
+	podTemplateHashLabel = "pod-template-hash"
+
+	// WorkloadOverflow is the workload_name label of workloads beyond the
+	// WorkloadResolver's MaxWorkloads.
+	WorkloadOverflow = "other"
+
+	// DefaultMaxMetricWorkloads is the default number of distinct workloads that get
+	// their own metric labels on each controller instance.
+	DefaultMaxMetricWorkloads = 500
+
+	// maxCachedReplicaSets caps the ReplicaSet owner cache. It is cleared when full;
+	// the ReplicaSets in use are looked up again on their next warmup.
+	maxCachedReplicaSets = 4096
+)
+
+// WorkloadResolver resolves the workload that owns a pod through its owner
+// references, for metric labels. Pods of a ReplicaSet are attributed to the
+// ReplicaSet's controller (a Deployment, or an Argo Rollout); other controllers,
+// such as StatefulSets and DaemonSets, are the workload themselves.
+//
+// ReplicaSet owners are looked up once and cached by ReplicaSet UID. Reader should
+// be an uncached reader, so that the node-local controllers do not watch every
+// ReplicaSet in the cluster.
+type WorkloadResolver struct {
+	Reader client.Reader
+
+	// MaxWorkloads caps the number of distinct workloads that get their own metric
+	// labels. Further workloads are labeled with their kind and WorkloadOverflow.
+	// 0 means no limit.
+	MaxWorkloads int
+
+	mu          sync.Mutex
+	replicaSets map[types.UID]metrics.Workload
+	labeled     map[workloadKey]struct{}
+}
+
+// workloadKey identifies a workload across namespaces.
+type workloadKey struct {
+	namespace string
+	metrics.Workload
+}
+
+// Resolve returns the workload that owns pod, or the zero Workload if the pod has no
+// controller. If the pod's ReplicaSet cannot be read, the workload is guessed from
+// the ReplicaSet's name (see guessWorkload) and the lookup is retried next time.
+func (r *WorkloadResolver) Resolve(ctx context.Context, pod *corev1.Pod) metrics.Workload {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return metrics.Workload{}
+	}
+	if owner.Kind != "ReplicaSet" || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+		return metrics.Workload{Kind: owner.Kind, Name: owner.Name}
+	}
+
+	r.mu.Lock()
+	workload, ok := r.replicaSets[owner.UID]
+	r.mu.Unlock()
+	if ok {
+		return workload
+	}
+
+	rs := &appsv1.ReplicaSet{}
+	if err := r.Reader.Get(ctx, types.NamespacedName{Namespace: pod.Namespace, Name: owner.Name}, rs); err != nil {
+		log.FromContext(ctx).V(1).Info("unable to look up the pod's ReplicaSet", "replicaSet", owner.Name, "error", err.Error())
+		return guessWorkload(pod)
+	}
+	workload = metrics.Workload{Kind: owner.Kind, Name: owner.Name}
+	if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil {
+		workload = metrics.Workload{Kind: rsOwner.Kind, Name: rsOwner.Name}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.replicaSets == nil || len(r.replicaSets) >= maxCachedReplicaSets {
+		r.replicaSets = make(map[types.UID]metrics.Workload)
+	}
+	r.replicaSets[owner.UID] = workload
+	return workload
+}
+
+// MetricLabels returns the workload labels of pod. Once MaxWorkloads distinct
+// workloads have been labeled, the name of any other workload is WorkloadOverflow.
+func (r *WorkloadResolver) MetricLabels(ctx context.Context, pod *corev1.Pod) metrics.Workload {
+	workload := r.Resolve(ctx, pod)
+	if workload == (metrics.Workload{}) {
+		return workload
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := workloadKey{namespace: pod.Namespace, Workload: workload}
+	if _, ok := r.labeled[key]; ok {
+		return workload
+	}
+	if r.MaxWorkloads > 0 && len(r.labeled) >= r.MaxWorkloads {
+		return metrics.Workload{Kind: workload.Kind, Name: WorkloadOverflow}
+	}
+	if r.labeled == nil {
+		r.labeled = make(map[workloadKey]struct{})
+	}
+	r.labeled[key] = struct{}{}
+	return workload
+}
+
+// guessWorkload names the workload that owns pod without API calls: a ReplicaSet
+// whose name ends with the pod's pod-template-hash label is taken to belong to the
+// Deployment named by the rest of its name.
+func guessWorkload(pod *corev1.Pod) metrics.Workload {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return metrics.Workload{}
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels[podTemplateHashLabel]; hash != "" {
+			if name, ok := strings.CutSuffix(owner.Name, "-"+hash); ok {
+				return metrics.Workload{Kind: "Deployment", Name: name}
+			}
+		}
+	}
+	return metrics.Workload{Kind: owner.Kind, Name: owner.Name}
+}
+
+// metricWorkload returns the workload labels of pod's metrics. The lookup is not
+// cancelled with ctx, so that cancelled warmups are attributed like the others.
+func (r *PodReconciler) metricWorkload(ctx context.Context, pod *corev1.Pod) metrics.Workload {
+	if r.Workloads == nil {
+		return guessWorkload(pod)
+	}
+	return r.Workloads.MetricLabels(context.WithoutCancel(ctx), pod)
+}
@@ -0,0 +1,183 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/hhiroshell/kube-booster/pkg/metrics"
+)
+
+// ownedBy returns a pod in namespace default controlled by apiVersion/kind name.
+func ownedBy(apiVersion, kind, name string, labels map[string]string) *corev1.Pod {
+	isController := true
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      "pod",
+		Namespace: "default",
+		Labels:    labels,
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Name:       name,
+			UID:        types.UID(name + "-uid"),
+			Controller: &isController,
+		}},
+	}}
+}
+
+// replicaSetOf returns a ReplicaSet in namespace default controlled by
+// apiVersion/kind owner, or by nothing if owner is empty.
+func replicaSetOf(name, apiVersion, kind, owner string) *appsv1.ReplicaSet {
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(name + "-uid")}}
+	if owner != "" {
+		isController := true
+		rs.OwnerReferences = []metav1.OwnerReference{{APIVersion: apiVersion, Kind: kind, Name: owner, Controller: &isController}}
+	}
+	return rs
+}
+
+func TestWorkloadResolver_Resolve(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+	hash := map[string]string{podTemplateHashLabel: "7d9f8"}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want metrics.Workload
+	}{
+		{name: "no owner", pod: &corev1.Pod{}},
+		{
+			name: "Deployment",
+			pod:  ownedBy("apps/v1", "ReplicaSet", "checkout-7d9f8", hash),
+			want: metrics.Workload{Kind: "Deployment", Name: "checkout"},
+		},
+		{
+			name: "Rollout",
+			pod:  ownedBy("apps/v1", "ReplicaSet", "search-5c4b", nil),
+			want: metrics.Workload{Kind: "Rollout", Name: "search"},
+		},
+		{
+			name: "bare ReplicaSet",
+			pod:  ownedBy("apps/v1", "ReplicaSet", "legacy", nil),
+			want: metrics.Workload{Kind: "ReplicaSet", Name: "legacy"},
+		},
+		{
+			name: "ReplicaSet not found",
+			pod:  ownedBy("apps/v1", "ReplicaSet", "gone-7d9f8", hash),
+			want: metrics.Workload{Kind: "Deployment", Name: "gone"},
+		},
+		{
+			name: "StatefulSet",
+			pod:  ownedBy("apps/v1", "StatefulSet", "db", nil),
+			want: metrics.Workload{Kind: "StatefulSet", Name: "db"},
+		},
+		{
+			name: "DaemonSet",
+			pod:  ownedBy("apps/v1", "DaemonSet", "agent", nil),
+			want: metrics.Workload{Kind: "DaemonSet", Name: "agent"},
+		},
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		replicaSetOf("checkout-7d9f8", "apps/v1", "Deployment", "checkout"),
+		replicaSetOf("search-5c4b", "argoproj.io/v1alpha1", "Rollout", "search"),
+		replicaSetOf("legacy", "", "", ""),
+	).Build()
+	r := &WorkloadResolver{Reader: c}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Resolve(context.Background(), tt.pod); got != tt.want {
+				t.Errorf("Resolve() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkloadResolver_CachesReplicaSets(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	gets := 0
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(replicaSetOf("checkout-7d9f8", "apps/v1", "Deployment", "checkout")).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				gets++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).
+		Build()
+	r := &WorkloadResolver{Reader: c}
+	pod := ownedBy("apps/v1", "ReplicaSet", "checkout-7d9f8", nil)
+
+	for range 3 {
+		if got := r.Resolve(context.Background(), pod); got != (metrics.Workload{Kind: "Deployment", Name: "checkout"}) {
+			t.Fatalf("Resolve() = %+v, want Deployment/checkout", got)
+		}
+	}
+	if gets != 1 {
+		t.Errorf("ReplicaSet looked up %d times, want 1", gets)
+	}
+}
+
+func TestWorkloadResolver_MetricLabels(t *testing.T) {
+	r := &WorkloadResolver{MaxWorkloads: 2}
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		pod  *corev1.Pod
+		want metrics.Workload
+	}{
+		{pod: ownedBy("apps/v1", "StatefulSet", "a", nil), want: metrics.Workload{Kind: "StatefulSet", Name: "a"}},
+		{pod: &corev1.Pod{}, want: metrics.Workload{}},
+		{pod: ownedBy("apps/v1", "DaemonSet", "b", nil), want: metrics.Workload{Kind: "DaemonSet", Name: "b"}},
+		// Over the limit
+		{pod: ownedBy("apps/v1", "StatefulSet", "c", nil), want: metrics.Workload{Kind: "StatefulSet", Name: WorkloadOverflow}},
+		// Already labeled
+		{pod: ownedBy("apps/v1", "StatefulSet", "a", nil), want: metrics.Workload{Kind: "StatefulSet", Name: "a"}},
+	} {
+		if got := r.MetricLabels(ctx, tt.pod); got != tt.want {
+			t.Errorf("MetricLabels(%v) = %+v, want %+v", tt.pod.OwnerReferences, got, tt.want)
+		}
+	}
+}
+
+func TestGuessWorkload(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want metrics.Workload
+	}{
+		{name: "no owner", pod: &corev1.Pod{}},
+		{
+			name: "Deployment",
+			pod:  ownedBy("apps/v1", "ReplicaSet", "checkout-7d9f8", map[string]string{podTemplateHashLabel: "7d9f8"}),
+			want: metrics.Workload{Kind: "Deployment", Name: "checkout"},
+		},
+		{
+			name: "bare ReplicaSet",
+			pod:  ownedBy("apps/v1", "ReplicaSet", "checkout", map[string]string{podTemplateHashLabel: "7d9f8"}),
+			want: metrics.Workload{Kind: "ReplicaSet", Name: "checkout"},
+		},
+		{name: "StatefulSet", pod: ownedBy("apps/v1", "StatefulSet", "db", nil), want: metrics.Workload{Kind: "StatefulSet", Name: "db"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guessWorkload(tt.pod); got != tt.want {
+				t.Errorf("guessWorkload() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,22 +6,25 @@ import (
 )
 
 var (
-	// WarmupTotal is a counter tracking total warmup executions
+	// WarmupTotal is a counter tracking total warmup executions.
+	// result is "success", "failure", or "cancelled" (pod deleted or changed mid-warmup).
 	WarmupTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "kube_booster_warmup_total",
 			Help: "Total warmup executions",
 		},
-		[]string{"namespace", "result"},
+		[]string{"namespace", "workload_kind", "workload_name", "result"},
 	)
 
-	// WarmupRequestsTotal is a counter tracking total HTTP requests sent during warmup
+	// WarmupRequestsTotal is a counter tracking total requests sent during warmup.
+	// outcome is the class of the response, e.g. "success", "http_5xx", "timeout" or
+	// "grpc_unavailable" (see warmup.ClassifyResponse).
 	WarmupRequestsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "kube_booster_warmup_requests_total",
-			Help: "Total HTTP requests sent during warmup",
+			Help: "Total requests sent during warmup, by outcome",
 		},
-		[]string{"namespace"},
+		[]string{"namespace", "workload_kind", "workload_name", "outcome"},
 	)
 
 	// WarmupDurationSeconds is a histogram tracking time from warmup start to completion.
@@ -33,7 +36,7 @@ var (
 			Help:    "Time from warmup start to completion",
 			Buckets: []float64{.5, 1, 2.5, 5, 10, 15, 30, 45, 60, 90, 120},
 		},
-		[]string{"namespace"},
+		[]string{"namespace", "workload_kind", "workload_name"},
 	)
 
 	// WarmupActivePods is a gauge tracking pods currently executing warmup requests
@@ -45,19 +48,104 @@ var (
 		[]string{"namespace", "node"},
 	)
 
-	// WarmupQueueWaitSeconds is a histogram tracking time pods wait for the warmup semaphore.
+	// WarmupQueueWaitSeconds is a histogram tracking time pods wait for a warmup concurrency slot.
 	// Custom buckets extend up to 300s (5 minutes) to cover high-contention scenarios where
 	// a pod waits behind multiple long-running warmups at a rate-limited cluster.
 	WarmupQueueWaitSeconds = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "kube_booster_warmup_queue_wait_seconds",
-			Help:    "Time pods wait for the warmup semaphore before execution begins",
+			Help:    "Time pods wait for a warmup concurrency slot before execution begins",
 			Buckets: []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
 		},
 		[]string{"namespace"},
 	)
+
+	// WarmupQueueDepth is a gauge tracking pods waiting for a warmup concurrency slot
+	WarmupQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "kube_booster_warmup_queue_depth",
+			Help: "Pods waiting for a warmup concurrency slot",
+		},
+		[]string{"namespace"},
+	)
+
+	// WarmupWatchdogTimeoutsTotal is a counter tracking pods whose readiness gate was
+	// released by the watchdog because no controller set the warmup condition in time
+	WarmupWatchdogTimeoutsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kube_booster_warmup_watchdog_timeouts_total",
+			Help: "Pods marked ready by the watchdog because the warmup condition was not set in time",
+		},
+		[]string{"namespace"},
+	)
+
+	// WarmupGateMissingTotal is a counter tracking pods that request warmup but were
+	// created without the readiness gate
+	WarmupGateMissingTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kube_booster_warmup_gate_missing_total",
+			Help: "Pods that request warmup but were created without the readiness gate",
+		},
+		[]string{"namespace"},
+	)
+
+	// WarmupStepFailuresTotal is a counter tracking WarmupConfig warmups in which a
+	// step had failed requests. warmup_config is the pod's warmup-config reference.
+	WarmupStepFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "kube_booster_warmup_step_failures_total",
+			Help: "WarmupConfig warmups in which the step had failed requests",
+		},
+		[]string{"namespace", "workload_kind", "workload_name", "warmup_config", "step"},
+	)
+
+	// WarmupFirstRequestSeconds is a histogram tracking the latency of the first
+	// warmup response, which usually pays for the application's cold start.
+	WarmupFirstRequestSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kube_booster_warmup_first_request_seconds",
+			Help:    "Latency of the first warmup response",
+			Buckets: latencyBuckets,
+		},
+		[]string{"namespace", "workload_kind", "workload_name"},
+	)
+
+	// WarmupFinalWindowSeconds is a histogram tracking the mean latency of the last
+	// warmup responses (the last 10% after the first).
+	WarmupFinalWindowSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kube_booster_warmup_final_window_seconds",
+			Help:    "Mean latency of the last warmup responses",
+			Buckets: latencyBuckets,
+		},
+		[]string{"namespace", "workload_kind", "workload_name"},
+	)
+
+	// WarmupSpeedupRatio is a histogram tracking the first-request latency divided by
+	// the final-window latency. Values well above 1 show that the workload has a
+	// cold-start penalty that warmup absorbs; values around 1 show that it has none.
+	WarmupSpeedupRatio = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "kube_booster_warmup_speedup_ratio",
+			Help:    "First warmup response latency divided by the mean latency of the last responses",
+			Buckets: []float64{0.5, 1, 1.5, 2, 3, 5, 10, 20, 50, 100},
+		},
+		[]string{"namespace", "workload_kind", "workload_name"},
+	)
 )
 
+// Workload identifies the workload that owns a pod in the workload_kind and
+// workload_name labels. The zero value leaves both labels empty, which Prometheus
+// treats as absent. The controller sets them only when workload labels are enabled.
+type Workload struct {
+	Kind string
+	Name string
+}
+
+// latencyBuckets are the buckets of the warmup response latency histograms, from
+// a warm in-memory response to a cold start that hits the 10s request timeout.
+var latencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
 func init() {
 	metrics.Registry.MustRegister(
 		WarmupTotal,
@@ -65,22 +153,36 @@ func init() {
 		WarmupDurationSeconds,
 		WarmupActivePods,
 		WarmupQueueWaitSeconds,
+		WarmupQueueDepth,
+		WarmupWatchdogTimeoutsTotal,
+		WarmupGateMissingTotal,
+		WarmupStepFailuresTotal,
+		WarmupFirstRequestSeconds,
+		WarmupFinalWindowSeconds,
+		WarmupSpeedupRatio,
 	)
 }
 
 // RecordWarmupResult records the outcome of a warmup execution
-func RecordWarmupResult(namespace string, success bool, durationSeconds float64) {
+func RecordWarmupResult(namespace string, workload Workload, success bool, durationSeconds float64) {
 	result := "failure"
 	if success {
 		result = "success"
 	}
-	WarmupTotal.WithLabelValues(namespace, result).Inc()
-	WarmupDurationSeconds.WithLabelValues(namespace).Observe(durationSeconds)
+	WarmupTotal.WithLabelValues(namespace, workload.Kind, workload.Name, result).Inc()
+	WarmupDurationSeconds.WithLabelValues(namespace, workload.Kind, workload.Name).Observe(durationSeconds)
+}
+
+// RecordWarmupCancelled records a warmup that was cancelled before it finished
+func RecordWarmupCancelled(namespace string, workload Workload) {
+	WarmupTotal.WithLabelValues(namespace, workload.Kind, workload.Name, "cancelled").Inc()
 }
 
-// RecordWarmupRequests records the number of HTTP requests sent during warmup
-func RecordWarmupRequests(namespace string, count int) {
-	WarmupRequestsTotal.WithLabelValues(namespace).Add(float64(count))
+// RecordWarmupRequests records the requests sent during warmup, counted by outcome
+func RecordWarmupRequests(namespace string, workload Workload, outcomes map[string]int) {
+	for outcome, count := range outcomes {
+		WarmupRequestsTotal.WithLabelValues(namespace, workload.Kind, workload.Name, outcome).Add(float64(count))
+	}
 }
 
 // IncrementWarmupActivePods increments the active warmup pods gauge for a namespace/node
@@ -99,7 +201,35 @@ func SetWarmupActivePods(namespace, node string, count float64) {
 	WarmupActivePods.WithLabelValues(namespace, node).Set(count)
 }
 
-// RecordWarmupQueueWait records the time a pod waited for the warmup semaphore.
+// RecordWarmupQueueWait records the time a pod waited for a warmup concurrency slot.
 func RecordWarmupQueueWait(namespace string, seconds float64) {
 	WarmupQueueWaitSeconds.WithLabelValues(namespace).Observe(seconds)
 }
+
+// SetWarmupQueueDepth sets the number of pods in a namespace waiting for a warmup slot.
+func SetWarmupQueueDepth(namespace string, depth int) {
+	WarmupQueueDepth.WithLabelValues(namespace).Set(float64(depth))
+}
+
+// RecordWarmupWatchdogTimeout records a pod released by the readiness watchdog.
+func RecordWarmupWatchdogTimeout(namespace string) {
+	WarmupWatchdogTimeoutsTotal.WithLabelValues(namespace).Inc()
+}
+
+// RecordWarmupGateMissing records a pod found requesting warmup without the readiness gate.
+func RecordWarmupGateMissing(namespace string) {
+	WarmupGateMissingTotal.WithLabelValues(namespace).Inc()
+}
+
+// RecordWarmupStepFailure records a WarmupConfig warmup in which step had failed requests.
+func RecordWarmupStepFailure(namespace string, workload Workload, warmupConfig, step string) {
+	WarmupStepFailuresTotal.WithLabelValues(namespace, workload.Kind, workload.Name, warmupConfig, step).Inc()
+}
+
+// RecordWarmupEffectiveness records the cold-start penalty measured by a warmup: the
+// first-request and final-window latencies and their ratio.
+func RecordWarmupEffectiveness(namespace string, workload Workload, firstSeconds, finalSeconds, speedupRatio float64) {
+	WarmupFirstRequestSeconds.WithLabelValues(namespace, workload.Kind, workload.Name).Observe(firstSeconds)
+	WarmupFinalWindowSeconds.WithLabelValues(namespace, workload.Kind, workload.Name).Observe(finalSeconds)
+	WarmupSpeedupRatio.WithLabelValues(namespace, workload.Kind, workload.Name).Observe(speedupRatio)
+}
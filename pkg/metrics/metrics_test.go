@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
@@ -12,16 +13,16 @@ func TestRecordWarmupResult_Success(t *testing.T) {
 	WarmupTotal.Reset()
 	WarmupDurationSeconds.Reset()
 
-	RecordWarmupResult("default", true, 5.0)
+	RecordWarmupResult("default", Workload{}, true, 5.0)
 
 	// Verify counter incremented
-	count := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "success"))
+	count := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "", "", "success"))
 	if count != 1 {
 		t.Errorf("expected warmup_total{namespace=default,result=success} = 1, got %f", count)
 	}
 
 	// Verify failure counter is 0
-	failureCount := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "failure"))
+	failureCount := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "", "", "failure"))
 	if failureCount != 0 {
 		t.Errorf("expected warmup_total{namespace=default,result=failure} = 0, got %f", failureCount)
 	}
@@ -31,15 +32,15 @@ func TestRecordWarmupResult_Failure(t *testing.T) {
 	WarmupTotal.Reset()
 	WarmupDurationSeconds.Reset()
 
-	RecordWarmupResult("kube-system", false, 10.0)
+	RecordWarmupResult("kube-system", Workload{}, false, 10.0)
 
-	count := testutil.ToFloat64(WarmupTotal.WithLabelValues("kube-system", "failure"))
+	count := testutil.ToFloat64(WarmupTotal.WithLabelValues("kube-system", "", "", "failure"))
 	if count != 1 {
 		t.Errorf("expected warmup_total{namespace=kube-system,result=failure} = 1, got %f", count)
 	}
 
 	// Verify success counter is 0
-	successCount := testutil.ToFloat64(WarmupTotal.WithLabelValues("kube-system", "success"))
+	successCount := testutil.ToFloat64(WarmupTotal.WithLabelValues("kube-system", "", "", "success"))
 	if successCount != 0 {
 		t.Errorf("expected warmup_total{namespace=kube-system,result=success} = 0, got %f", successCount)
 	}
@@ -49,47 +50,69 @@ func TestRecordWarmupResult_MultipleNamespaces(t *testing.T) {
 	WarmupTotal.Reset()
 	WarmupDurationSeconds.Reset()
 
-	RecordWarmupResult("ns1", true, 1.0)
-	RecordWarmupResult("ns2", true, 2.0)
-	RecordWarmupResult("ns1", false, 3.0)
+	RecordWarmupResult("ns1", Workload{}, true, 1.0)
+	RecordWarmupResult("ns2", Workload{}, true, 2.0)
+	RecordWarmupResult("ns1", Workload{}, false, 3.0)
 
 	// ns1 should have 1 success and 1 failure
-	ns1Success := testutil.ToFloat64(WarmupTotal.WithLabelValues("ns1", "success"))
-	ns1Failure := testutil.ToFloat64(WarmupTotal.WithLabelValues("ns1", "failure"))
+	ns1Success := testutil.ToFloat64(WarmupTotal.WithLabelValues("ns1", "", "", "success"))
+	ns1Failure := testutil.ToFloat64(WarmupTotal.WithLabelValues("ns1", "", "", "failure"))
 	if ns1Success != 1 || ns1Failure != 1 {
 		t.Errorf("expected ns1 success=1, failure=1, got success=%f, failure=%f", ns1Success, ns1Failure)
 	}
 
 	// ns2 should have 1 success
-	ns2Success := testutil.ToFloat64(WarmupTotal.WithLabelValues("ns2", "success"))
+	ns2Success := testutil.ToFloat64(WarmupTotal.WithLabelValues("ns2", "", "", "success"))
 	if ns2Success != 1 {
 		t.Errorf("expected ns2 success=1, got %f", ns2Success)
 	}
 }
 
+func TestRecordWarmupResult_WorkloadLabels(t *testing.T) {
+	WarmupTotal.Reset()
+	WarmupDurationSeconds.Reset()
+
+	RecordWarmupResult("default", Workload{Kind: "Deployment", Name: "checkout"}, true, 1.0)
+	RecordWarmupResult("default", Workload{Kind: "StatefulSet", Name: "db"}, false, 2.0)
+	RecordWarmupResult("default", Workload{}, true, 3.0)
+
+	if got := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "Deployment", "checkout", "success")); got != 1 {
+		t.Errorf("expected warmup_total{workload_kind=Deployment,workload_name=checkout,result=success} = 1, got %f", got)
+	}
+	if got := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "StatefulSet", "db", "failure")); got != 1 {
+		t.Errorf("expected warmup_total{workload_kind=StatefulSet,workload_name=db,result=failure} = 1, got %f", got)
+	}
+	if got := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "", "", "success")); got != 1 {
+		t.Errorf("expected warmup_total{result=success} without workload = 1, got %f", got)
+	}
+	if got := testutil.CollectAndCount(WarmupDurationSeconds); got != 3 {
+		t.Errorf("expected 3 warmup_duration_seconds series, got %d", got)
+	}
+}
+
 func TestRecordWarmupResult_RecordsDuration(t *testing.T) {
 	WarmupTotal.Reset()
 	WarmupDurationSeconds.Reset()
 
-	RecordWarmupResult("default", true, 5.0)
+	RecordWarmupResult("default", Workload{}, true, 5.0)
 
 	expected := strings.NewReader(`
 # HELP kube_booster_warmup_duration_seconds Time from warmup start to completion
 # TYPE kube_booster_warmup_duration_seconds histogram
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.005"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.01"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.025"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.05"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.1"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.25"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="0.5"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="1"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="2.5"} 0
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="5"} 1
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="10"} 1
-kube_booster_warmup_duration_seconds_bucket{namespace="default",le="+Inf"} 1
-kube_booster_warmup_duration_seconds_sum{namespace="default"} 5
-kube_booster_warmup_duration_seconds_count{namespace="default"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="0.5"} 0
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="1"} 0
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="2.5"} 0
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="5"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="10"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="15"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="30"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="45"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="60"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="90"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="120"} 1
+kube_booster_warmup_duration_seconds_bucket{namespace="default",workload_kind="",workload_name="",le="+Inf"} 1
+kube_booster_warmup_duration_seconds_sum{namespace="default",workload_kind="",workload_name=""} 5
+kube_booster_warmup_duration_seconds_count{namespace="default",workload_kind="",workload_name=""} 1
 `)
 
 	if err := testutil.CollectAndCompare(WarmupDurationSeconds, expected, "kube_booster_warmup_duration_seconds"); err != nil {
@@ -100,21 +123,22 @@ kube_booster_warmup_duration_seconds_count{namespace="default"} 1
 func TestRecordWarmupRequests(t *testing.T) {
 	WarmupRequestsTotal.Reset()
 
-	RecordWarmupRequests("default", 100)
+	RecordWarmupRequests("default", Workload{}, map[string]int{"success": 97, "http_5xx": 2, "timeout": 1})
 
-	count := testutil.ToFloat64(WarmupRequestsTotal.WithLabelValues("default"))
-	if count != 100 {
-		t.Errorf("expected warmup_requests_total = 100, got %f", count)
+	for outcome, want := range map[string]float64{"success": 97, "http_5xx": 2, "timeout": 1} {
+		if got := testutil.ToFloat64(WarmupRequestsTotal.WithLabelValues("default", "", "", outcome)); got != want {
+			t.Errorf("expected warmup_requests_total{outcome=%s} = %v, got %f", outcome, want, got)
+		}
 	}
 }
 
 func TestRecordWarmupRequests_Accumulates(t *testing.T) {
 	WarmupRequestsTotal.Reset()
 
-	RecordWarmupRequests("default", 50)
-	RecordWarmupRequests("default", 30)
+	RecordWarmupRequests("default", Workload{}, map[string]int{"success": 50})
+	RecordWarmupRequests("default", Workload{}, map[string]int{"success": 30})
 
-	count := testutil.ToFloat64(WarmupRequestsTotal.WithLabelValues("default"))
+	count := testutil.ToFloat64(WarmupRequestsTotal.WithLabelValues("default", "", "", "success"))
 	if count != 80 {
 		t.Errorf("expected warmup_requests_total = 80, got %f", count)
 	}
@@ -161,7 +185,7 @@ func TestRecordWarmupQueueWait(t *testing.T) {
 	RecordWarmupQueueWait("default", 2.0)
 
 	expected := strings.NewReader(`
-# HELP kube_booster_warmup_queue_wait_seconds Time pods wait for the warmup semaphore before execution begins
+# HELP kube_booster_warmup_queue_wait_seconds Time pods wait for a warmup concurrency slot before execution begins
 # TYPE kube_booster_warmup_queue_wait_seconds histogram
 kube_booster_warmup_queue_wait_seconds_bucket{namespace="default",le="0.5"} 0
 kube_booster_warmup_queue_wait_seconds_bucket{namespace="default",le="1"} 0
@@ -204,3 +228,105 @@ func TestWarmupActivePods_MultipleNodes(t *testing.T) {
 		t.Errorf("expected kube-system/node-1 = 1, got %f", node1KubeSystem)
 	}
 }
+
+func TestSetWarmupQueueDepth(t *testing.T) {
+	WarmupQueueDepth.Reset()
+
+	SetWarmupQueueDepth("team-a", 3)
+	SetWarmupQueueDepth("team-b", 1)
+	SetWarmupQueueDepth("team-a", 2)
+
+	if got := testutil.ToFloat64(WarmupQueueDepth.WithLabelValues("team-a")); got != 2 {
+		t.Errorf("expected warmup_queue_depth{namespace=team-a} = 2, got %f", got)
+	}
+	if got := testutil.ToFloat64(WarmupQueueDepth.WithLabelValues("team-b")); got != 1 {
+		t.Errorf("expected warmup_queue_depth{namespace=team-b} = 1, got %f", got)
+	}
+}
+
+func TestRecordWarmupCancelled(t *testing.T) {
+	WarmupTotal.Reset()
+
+	RecordWarmupCancelled("default", Workload{})
+
+	if got := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "", "", "cancelled")); got != 1 {
+		t.Errorf("expected warmup_total{namespace=default,result=cancelled} = 1, got %f", got)
+	}
+	if got := testutil.ToFloat64(WarmupTotal.WithLabelValues("default", "", "", "failure")); got != 0 {
+		t.Errorf("expected warmup_total{namespace=default,result=failure} = 0, got %f", got)
+	}
+}
+
+func TestRecordWarmupWatchdogTimeout(t *testing.T) {
+	WarmupWatchdogTimeoutsTotal.Reset()
+
+	RecordWarmupWatchdogTimeout("default")
+	RecordWarmupWatchdogTimeout("default")
+
+	if got := testutil.ToFloat64(WarmupWatchdogTimeoutsTotal.WithLabelValues("default")); got != 2 {
+		t.Errorf("expected warmup_watchdog_timeouts_total{namespace=default} = 2, got %f", got)
+	}
+}
+
+func TestRecordWarmupGateMissing(t *testing.T) {
+	WarmupGateMissingTotal.Reset()
+
+	RecordWarmupGateMissing("default")
+
+	if got := testutil.ToFloat64(WarmupGateMissingTotal.WithLabelValues("default")); got != 1 {
+		t.Errorf("expected warmup_gate_missing_total{namespace=default} = 1, got %f", got)
+	}
+}
+
+func TestRecordWarmupStepFailure(t *testing.T) {
+	WarmupStepFailuresTotal.Reset()
+
+	RecordWarmupStepFailure("default", Workload{}, "catalog", "browse")
+	RecordWarmupStepFailure("default", Workload{}, "catalog", "browse")
+	RecordWarmupStepFailure("default", Workload{}, "catalog", "login")
+
+	if got := testutil.ToFloat64(WarmupStepFailuresTotal.WithLabelValues("default", "", "", "catalog", "browse")); got != 2 {
+		t.Errorf("expected warmup_step_failures_total{step=browse} = 2, got %f", got)
+	}
+	if got := testutil.ToFloat64(WarmupStepFailuresTotal.WithLabelValues("default", "", "", "catalog", "login")); got != 1 {
+		t.Errorf("expected warmup_step_failures_total{step=login} = 1, got %f", got)
+	}
+}
+
+func TestRecordWarmupEffectiveness(t *testing.T) {
+	WarmupFirstRequestSeconds.Reset()
+	WarmupFinalWindowSeconds.Reset()
+	WarmupSpeedupRatio.Reset()
+
+	RecordWarmupEffectiveness("default", Workload{Kind: "Deployment", Name: "checkout"}, 0.8, 0.02, 40)
+
+	for name, vec := range map[string]*prometheus.HistogramVec{
+		"first_request_seconds": WarmupFirstRequestSeconds,
+		"final_window_seconds":  WarmupFinalWindowSeconds,
+		"speedup_ratio":         WarmupSpeedupRatio,
+	} {
+		if got := testutil.CollectAndCount(vec); got != 1 {
+			t.Errorf("expected 1 %s series, got %d", name, got)
+		}
+	}
+	expected := `
+		# HELP kube_booster_warmup_speedup_ratio First warmup response latency divided by the mean latency of the last responses
+		# TYPE kube_booster_warmup_speedup_ratio histogram
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="0.5"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="1"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="1.5"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="2"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="3"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="5"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="10"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="20"} 0
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="50"} 1
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="100"} 1
+		kube_booster_warmup_speedup_ratio_bucket{namespace="default",workload_kind="Deployment",workload_name="checkout",le="+Inf"} 1
+		kube_booster_warmup_speedup_ratio_sum{namespace="default",workload_kind="Deployment",workload_name="checkout"} 40
+		kube_booster_warmup_speedup_ratio_count{namespace="default",workload_kind="Deployment",workload_name="checkout"} 1
+	`
+	if err := testutil.CollectAndCompare(WarmupSpeedupRatio, strings.NewReader(expected)); err != nil {
+		t.Errorf("unexpected speedup ratio histogram: %v", err)
+	}
+}
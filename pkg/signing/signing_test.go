@@ -0,0 +1,156 @@
+package signing
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("test-signing-key")
+
+func TestNewSigner_EmptyKey(t *testing.T) {
+	if s := NewSigner(nil); s != nil {
+		t.Error("NewSigner(nil) should return nil")
+	}
+}
+
+func TestSigner_NilReceiver(t *testing.T) {
+	var s *Signer
+	if h := s.Headers("GET", "/", nil); h != nil {
+		t.Errorf("nil Signer Headers() = %v, want nil", h)
+	}
+	if ts, sig := s.Sign("GET", "/", nil); ts != "" || sig != "" {
+		t.Errorf("nil Signer Sign() = (%q, %q), want empty", ts, sig)
+	}
+}
+
+func TestSignAndVerify_RoundTrip(t *testing.T) {
+	signer := NewSigner(testKey)
+	verifier := NewVerifier(testKey, 0)
+
+	body := []byte(`{"action":"preload"}`)
+	ts, sig := signer.Sign("POST", "/api/cache?x=1", body)
+	if !strings.HasPrefix(sig, "v1=") {
+		t.Errorf("signature %q missing version prefix", sig)
+	}
+	if err := verifier.Verify("POST", "/api/cache?x=1", body, ts, sig); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestVerifier_Verify_Rejects(t *testing.T) {
+	signer := NewSigner(testKey)
+	body := []byte("payload")
+	ts, sig := signer.Sign("POST", "/warmup", body)
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		method   string
+		path     string
+		body     []byte
+		ts       string
+		sig      string
+		wantErr  error
+	}{
+		{name: "missing signature", verifier: NewVerifier(testKey, 0), method: "POST", path: "/warmup", body: body, ts: ts, wantErr: ErrMissingSignature},
+		{name: "wrong key", verifier: NewVerifier([]byte("other"), 0), method: "POST", path: "/warmup", body: body, ts: ts, sig: sig, wantErr: ErrInvalidSignature},
+		{name: "tampered body", verifier: NewVerifier(testKey, 0), method: "POST", path: "/warmup", body: []byte("other"), ts: ts, sig: sig, wantErr: ErrInvalidSignature},
+		{name: "tampered path", verifier: NewVerifier(testKey, 0), method: "POST", path: "/admin", body: body, ts: ts, sig: sig, wantErr: ErrInvalidSignature},
+		{name: "tampered method", verifier: NewVerifier(testKey, 0), method: "GET", path: "/warmup", body: body, ts: ts, sig: sig, wantErr: ErrInvalidSignature},
+		{name: "non-numeric timestamp", verifier: NewVerifier(testKey, 0), method: "POST", path: "/warmup", body: body, ts: "abc", sig: sig, wantErr: ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.verifier.Verify(tt.method, tt.path, tt.body, tt.ts, tt.sig)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify_TimestampSkew(t *testing.T) {
+	signer := NewSigner(testKey)
+	signer.now = func() time.Time { return time.Unix(1000, 0) }
+	ts, sig := signer.Sign("GET", "/", nil)
+
+	verifier := NewVerifier(testKey, time.Minute)
+	verifier.now = func() time.Time { return time.Unix(1000, 0).Add(2 * time.Minute) }
+	if err := verifier.Verify("GET", "/", nil, ts, sig); !errors.Is(err, ErrTimestampSkew) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrTimestampSkew)
+	}
+
+	verifier.now = func() time.Time { return time.Unix(1000, 0).Add(30 * time.Second) }
+	if err := verifier.Verify("GET", "/", nil, ts, sig); err != nil {
+		t.Errorf("Verify() within skew error = %v", err)
+	}
+}
+
+func TestVerifier_VerifyRequest_PreservesBody(t *testing.T) {
+	signer := NewSigner(testKey)
+	body := `{"userId":"warmup"}`
+
+	req := httptest.NewRequest(http.MethodPost, "/api/recs?limit=5", strings.NewReader(body))
+	for k, v := range signer.Headers(http.MethodPost, "/api/recs?limit=5", []byte(body)) {
+		req.Header.Set(k, v)
+	}
+
+	if err := NewVerifier(testKey, 0).VerifyRequest(req); err != nil {
+		t.Fatalf("VerifyRequest() error = %v", err)
+	}
+	got, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("reading body after verification: %v", err)
+	}
+	if string(got) != body {
+		t.Errorf("body after VerifyRequest() = %q, want %q", got, body)
+	}
+}
+
+func TestVerifier_Middleware(t *testing.T) {
+	signer := NewSigner(testKey)
+	verifier := NewVerifier(testKey, 0)
+
+	var verified bool
+	handler := verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verified = IsVerifiedWarmup(r.Context())
+	}))
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{name: "signed request", headers: signer.Headers(http.MethodGet, "/", nil), want: true},
+		{name: "spoofed warmup header", headers: map[string]string{"X-Warmup-Request": "true"}, want: false},
+		{name: "bad signature", headers: map[string]string{
+			HeaderTimestamp: strconv.FormatInt(time.Now().Unix(), 10),
+			HeaderSignature: "v1=deadbeef",
+		}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified = false
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("middleware status = %d, want 200 (never rejects)", rec.Code)
+			}
+			if verified != tt.want {
+				t.Errorf("IsVerifiedWarmup() = %v, want %v", verified, tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package warmup
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -69,14 +72,57 @@ type Config struct {
 	// GRPCPayload is the JSON-encoded request payload for gRPC warmup, defaults to "{}"
 	GRPCPayload string
 
-	// WarmupConfigName is the name of a WarmupConfig CR in the pod's namespace.
+	// Stages is the ramp-up load schedule (from kube-booster.io/warmup-stages).
+	// When set, requests are paced by the stages and the warmup ends after the final
+	// stage; RequestCount is ignored.
+	Stages []Stage
+
+	// RPS caps this pod's warmup request rate (from kube-booster.io/warmup-rps).
+	// It applies on top of the controller-wide --max-warmup-rps limit. 0 means no
+	// per-pod limit.
+	RPS float64
+
+	// WarmupConfigName is the name of a WarmupConfig CR in the pod's namespace, or
+	// of a ClusterWarmupConfig when WarmupConfigKind says so.
 	// When non-empty, the controller uses scenario-based warmup instead of the
 	// single-endpoint annotation-based warmup.
 	WarmupConfigName string
+
+	// WarmupConfigKind is webhook.KindWarmupConfig or webhook.KindClusterWarmupConfig
+	// when WarmupConfigName is set.
+	WarmupConfigKind string
+
+	// Params are the WarmupConfig parameters set by the pod (from
+	// kube-booster.io/warmup-config-params and kube-booster.io/param.<name>). The
+	// controller replaces them with the resolved values of the parameters the
+	// WarmupConfig declares (see ResolveParams) before the scenario runs.
+	Params map[string]string
+
+	// Progress is the progress of an earlier, interrupted attempt (set by controller).
+	// Scenario warmups resume from the first step it does not cover.
+	Progress *Progress
+
+	// OnProgress, if set, is called by scenario warmups after each step they record
+	// as completed, except the last, with the steps completed so far and the
+	// variables that may be persisted (set by controller). Attempt is left unset.
+	OnProgress func(progress *Progress)
+
+	// Sources maps each annotation key whose value was set by the pod or inherited
+	// to where the value came from: webhook.SettingSourcePod,
+	// webhook.SettingSourceNamespace, or a policy (see webhook.MatchedPolicy.String).
+	// Settings left at their defaults are absent.
+	Sources map[string]string
 }
 
 // ParseConfig parses warmup configuration from pod annotations
 func ParseConfig(pod *corev1.Pod) (*Config, error) {
+	return ParseConfigWithDefaults(pod, webhook.Defaults{})
+}
+
+// ParseConfigWithDefaults parses warmup configuration from pod annotations, using
+// the settings of the matching policy and the namespace's annotations as defaults
+// (see webhook.Defaults.EffectiveAnnotations).
+func ParseConfigWithDefaults(pod *corev1.Pod, defaults webhook.Defaults) (*Config, error) {
 	config := &Config{
 		Endpoint:     DefaultEndpointPath,
 		RequestCount: DefaultRequestCount,
@@ -90,8 +136,9 @@ func ParseConfig(pod *corev1.Pod) (*Config, error) {
 	}
 
 	// Parse annotations if present
-	annotations := pod.Annotations
-	if annotations != nil {
+	annotations, sources := defaults.EffectiveAnnotations(pod)
+	config.Sources = sources
+	if len(annotations) > 0 {
 		// Parse endpoint
 		if endpoint, ok := annotations[webhook.AnnotationWarmupEndpoint]; ok && endpoint != "" {
 			config.Endpoint = endpoint
@@ -127,6 +174,30 @@ func ParseConfig(pod *corev1.Pod) (*Config, error) {
 			config.Timeout = timeout
 		}
 
+		// Parse load stages
+		if stagesStr, ok := annotations[webhook.AnnotationWarmupStages]; ok && stagesStr != "" {
+			stages, err := ParseStages(stagesStr)
+			if err != nil {
+				return config, fmt.Errorf("invalid warmup-stages value %q: %w", stagesStr, err)
+			}
+			if total := TotalStageDuration(stages); total > config.Timeout {
+				return config, fmt.Errorf("warmup-stages total duration %v exceeds warmup-timeout %v", total, config.Timeout)
+			}
+			config.Stages = stages
+		}
+
+		// Parse per-pod request rate
+		if rpsStr, ok := annotations[webhook.AnnotationWarmupRPS]; ok && rpsStr != "" {
+			rps, err := strconv.ParseFloat(rpsStr, 64)
+			if err != nil {
+				return config, fmt.Errorf("invalid warmup-rps value %q: %w", rpsStr, err)
+			}
+			if math.IsNaN(rps) || math.IsInf(rps, 0) || rps <= 0 {
+				return config, fmt.Errorf("warmup-rps must be a positive number, got %q", rpsStr)
+			}
+			config.RPS = rps
+		}
+
 		// Parse protocol
 		if protocol, ok := annotations[webhook.AnnotationWarmupProtocol]; ok && protocol != "" {
 			switch protocol {
@@ -160,10 +231,22 @@ func ParseConfig(pod *corev1.Pod) (*Config, error) {
 		}
 
 		// Parse WarmupConfig reference
-		if name, ok := annotations[webhook.AnnotationWarmupConfig]; ok && name != "" {
+		if ref, ok := annotations[webhook.AnnotationWarmupConfig]; ok && ref != "" {
+			kind, name, err := ParseWarmupConfigRef(ref)
+			if err != nil {
+				return config, fmt.Errorf("invalid warmup-config value %q: %w", ref, err)
+			}
+			config.WarmupConfigKind = kind
 			config.WarmupConfigName = name
 		}
 
+		// Parse WarmupConfig parameters
+		params, err := ParseParams(annotations)
+		if err != nil {
+			return config, err
+		}
+		config.Params = params
+
 		// Parse port from annotation
 		if portStr, ok := annotations[webhook.AnnotationWarmupPort]; ok && portStr != "" {
 			port, err := strconv.Atoi(portStr)
@@ -199,11 +282,118 @@ func ParseConfig(pod *corev1.Pod) (*Config, error) {
 		webhook.AnnotationWarmupPort)
 }
 
+// ParseWarmupConfigRef splits a warmup-config annotation value into the kind and
+// name of the referenced config. A bare name refers to a WarmupConfig; a
+// "ClusterWarmupConfig/<name>" (or "WarmupConfig/<name>") prefix selects the kind.
+func ParseWarmupConfigRef(ref string) (kind, name string, err error) {
+	kind, name, ok := strings.Cut(ref, "/")
+	if !ok {
+		kind, name = webhook.KindWarmupConfig, ref
+	}
+	if kind != webhook.KindWarmupConfig && kind != webhook.KindClusterWarmupConfig {
+		return "", "", fmt.Errorf("kind must be %q or %q, got %q", webhook.KindWarmupConfig, webhook.KindClusterWarmupConfig, kind)
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("name must not be empty")
+	}
+	return kind, name, nil
+}
+
+// WarmupConfigRef returns the warmup-config reference in annotation form: the bare
+// name for a WarmupConfig, "ClusterWarmupConfig/<name>" for a ClusterWarmupConfig,
+// or "" if none is set.
+func (c *Config) WarmupConfigRef() string {
+	if c.WarmupConfigName != "" && c.WarmupConfigKind == webhook.KindClusterWarmupConfig {
+		return c.WarmupConfigKind + "/" + c.WarmupConfigName
+	}
+	return c.WarmupConfigName
+}
+
+// ParseStages parses comma-separated "<rps>:<duration>" pairs (e.g. "5:10s,20:10s")
+// into load stages. Each stage needs a positive rate and a positive duration.
+func ParseStages(s string) ([]Stage, error) {
+	parts := strings.Split(s, ",")
+	stages := make([]Stage, 0, len(parts))
+	for _, part := range parts {
+		rpsStr, durStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("stage %q: expected format \"<rps>:<duration>\"", part)
+		}
+		stage, err := parseStage(rpsStr, durStr)
+		if err != nil {
+			return nil, fmt.Errorf("stage %q: %w", part, err)
+		}
+		stages = append(stages, stage)
+	}
+	return stages, nil
+}
+
+// parseStage validates and converts a single stage's rate and duration.
+func parseStage(rpsStr, durStr string) (Stage, error) {
+	rps, err := strconv.ParseFloat(strings.TrimSpace(rpsStr), 64)
+	if err != nil {
+		return Stage{}, fmt.Errorf("invalid rate %q: %w", rpsStr, err)
+	}
+	if rps <= 0 {
+		return Stage{}, fmt.Errorf("rate must be positive, got %v", rps)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(durStr))
+	if err != nil {
+		return Stage{}, fmt.Errorf("invalid duration %q: %w", durStr, err)
+	}
+	if d <= 0 {
+		return Stage{}, fmt.Errorf("duration must be positive, got %v", d)
+	}
+	if d > MaxTimeout {
+		return Stage{}, fmt.Errorf("duration must not exceed %v, got %v", MaxTimeout, d)
+	}
+	return Stage{RPS: rps, Duration: d}, nil
+}
+
 // BuildGRPCAddress returns the "host:port" address for gRPC dial
 func (c *Config) BuildGRPCAddress() string {
 	return fmt.Sprintf("%s:%d", c.PodIP, c.Port)
 }
 
+// DescribeSources returns a human-readable summary of the inheritable settings and
+// where each came from, e.g. "warmup-endpoint=/warm (namespace), warmup-requests=3 (default)".
+// Endpoint, requests, timeout, and config are always listed when set; other
+// settings only when they were inherited or set by the pod.
+func (c *Config) DescribeSources() string {
+	values := map[string]string{
+		webhook.AnnotationWarmupEndpoint:    c.Endpoint,
+		webhook.AnnotationWarmupRequests:    strconv.Itoa(c.RequestCount),
+		webhook.AnnotationWarmupTimeout:     c.Timeout.String(),
+		webhook.AnnotationWarmupConfig:      c.WarmupConfigRef(),
+		webhook.AnnotationWarmupPort:        strconv.Itoa(c.Port),
+		webhook.AnnotationWarmupProtocol:    c.Protocol,
+		webhook.AnnotationWarmupGRPCMethod:  c.GRPCMethod,
+		webhook.AnnotationWarmupGRPCPayload: c.GRPCPayload,
+	}
+	keys := slices.Clone(webhook.NamespaceDefaultAnnotations)
+	var others []string
+	for key := range c.Sources {
+		if _, ok := values[key]; ok && !slices.Contains(keys, key) {
+			others = append(others, key)
+		}
+	}
+	slices.Sort(others)
+	keys = append(keys, others...)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		source, ok := c.Sources[key]
+		if !ok {
+			if values[key] == "" {
+				continue
+			}
+			source = "default"
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s (%s)", strings.TrimPrefix(key, "kube-booster.io/"), values[key], source))
+	}
+	return strings.Join(parts, ", ")
+}
+
 // BuildEndpointURL constructs the full URL for warmup requests
 func (c *Config) BuildEndpointURL() string {
 	endpoint := c.Endpoint
@@ -1,6 +1,7 @@
 package warmup
 
 import (
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -498,6 +499,89 @@ func TestParseConfig(t *testing.T) {
 			wantErr:     true,
 			errContains: "invalid warmup-protocol value",
 		},
+		{
+			name: "per-pod rate limit",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: "default",
+					Annotations: map[string]string{
+						webhook.AnnotationWarmupRPS:  "2.5",
+						webhook.AnnotationWarmupPort: "8080",
+					},
+				},
+			},
+			wantConfig: &Config{
+				Endpoint:     DefaultEndpointPath,
+				RequestCount: DefaultRequestCount,
+				Timeout:      DefaultTimeout,
+				Protocol:     ProtocolHTTP,
+				GRPCPayload:  DefaultGRPCPayload,
+				Port:         8080,
+				RPS:          2.5,
+			},
+		},
+		{
+			name: "non-numeric rate limit returns error",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: "default",
+					Annotations: map[string]string{
+						webhook.AnnotationWarmupRPS:  "fast",
+						webhook.AnnotationWarmupPort: "8080",
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "invalid warmup-rps value",
+		},
+		{
+			name: "zero rate limit returns error",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: "default",
+					Annotations: map[string]string{
+						webhook.AnnotationWarmupRPS:  "0",
+						webhook.AnnotationWarmupPort: "8080",
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "warmup-rps must be a positive number",
+		},
+		{
+			name: "invalid stages returns error",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: "default",
+					Annotations: map[string]string{
+						webhook.AnnotationWarmupStages: "5:10s,fast:10s",
+						webhook.AnnotationWarmupPort:   "8080",
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "invalid warmup-stages value",
+		},
+		{
+			name: "stages longer than timeout returns error",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-pod",
+					Namespace: "default",
+					Annotations: map[string]string{
+						webhook.AnnotationWarmupStages:  "5:20s,20:20s",
+						webhook.AnnotationWarmupTimeout: "30s",
+						webhook.AnnotationWarmupPort:    "8080",
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "exceeds warmup-timeout",
+		},
 	}
 
 	for _, tt := range tests {
@@ -541,6 +625,9 @@ func TestParseConfig(t *testing.T) {
 			if config.GRPCPayload != tt.wantConfig.GRPCPayload {
 				t.Errorf("GRPCPayload = %v, want %v", config.GRPCPayload, tt.wantConfig.GRPCPayload)
 			}
+			if config.RPS != tt.wantConfig.RPS {
+				t.Errorf("RPS = %v, want %v", config.RPS, tt.wantConfig.RPS)
+			}
 			if config.WarmupConfigName != tt.wantConfig.WarmupConfigName {
 				t.Errorf("WarmupConfigName = %v, want %v", config.WarmupConfigName, tt.wantConfig.WarmupConfigName)
 			}
@@ -548,6 +635,206 @@ func TestParseConfig(t *testing.T) {
 	}
 }
 
+func TestParseConfigWithDefaults(t *testing.T) {
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			Annotations: map[string]string{
+				webhook.AnnotationWarmupEndpoint: "/warm",
+				webhook.AnnotationWarmupTimeout:  "45s",
+				webhook.AnnotationWarmupConfig:   "team-default",
+				// Not a defaultable setting: ignored
+				webhook.AnnotationWarmupPort: "9090",
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		annotations     map[string]string
+		ns              *corev1.Namespace
+		wantEndpoint    string
+		wantTimeout     time.Duration
+		wantConfigName  string
+		wantSources     map[string]string
+		wantDescription string
+	}{
+		{
+			name:           "namespace supplies defaults",
+			annotations:    map[string]string{webhook.AnnotationWarmupPort: "8080"},
+			ns:             ns,
+			wantEndpoint:   "/warm",
+			wantTimeout:    45 * time.Second,
+			wantConfigName: "team-default",
+			wantSources: map[string]string{
+				webhook.AnnotationWarmupEndpoint: webhook.SettingSourceNamespace,
+				webhook.AnnotationWarmupTimeout:  webhook.SettingSourceNamespace,
+				webhook.AnnotationWarmupConfig:   webhook.SettingSourceNamespace,
+			},
+			wantDescription: "warmup-endpoint=/warm (namespace), warmup-requests=3 (default), " +
+				"warmup-timeout=45s (namespace), warmup-config=team-default (namespace)",
+		},
+		{
+			name: "pod annotations override namespace",
+			annotations: map[string]string{
+				webhook.AnnotationWarmupPort:     "8080",
+				webhook.AnnotationWarmupEndpoint: "/pod",
+				webhook.AnnotationWarmupConfig:   "own",
+			},
+			ns:             ns,
+			wantEndpoint:   "/pod",
+			wantTimeout:    45 * time.Second,
+			wantConfigName: "own",
+			wantSources: map[string]string{
+				webhook.AnnotationWarmupEndpoint: webhook.SettingSourcePod,
+				webhook.AnnotationWarmupTimeout:  webhook.SettingSourceNamespace,
+				webhook.AnnotationWarmupConfig:   webhook.SettingSourcePod,
+			},
+			wantDescription: "warmup-endpoint=/pod (pod annotation), warmup-requests=3 (default), " +
+				"warmup-timeout=45s (namespace), warmup-config=own (pod annotation)",
+		},
+		{
+			name:            "nil namespace uses pod annotations only",
+			annotations:     map[string]string{webhook.AnnotationWarmupPort: "8080"},
+			wantEndpoint:    DefaultEndpointPath,
+			wantTimeout:     DefaultTimeout,
+			wantSources:     map[string]string{},
+			wantDescription: "warmup-endpoint=/ (default), warmup-requests=3 (default), warmup-timeout=30s (default)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "team-a", Annotations: tt.annotations},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			}
+			config, err := ParseConfigWithDefaults(pod, webhook.Defaults{Namespace: tt.ns})
+			if err != nil {
+				t.Fatalf("ParseConfigWithDefaults() error = %v", err)
+			}
+			if config.Port != 8080 {
+				t.Errorf("Port = %d, want 8080 (namespace port annotation must be ignored)", config.Port)
+			}
+			if config.Endpoint != tt.wantEndpoint {
+				t.Errorf("Endpoint = %q, want %q", config.Endpoint, tt.wantEndpoint)
+			}
+			if config.Timeout != tt.wantTimeout {
+				t.Errorf("Timeout = %v, want %v", config.Timeout, tt.wantTimeout)
+			}
+			if config.WarmupConfigName != tt.wantConfigName {
+				t.Errorf("WarmupConfigName = %q, want %q", config.WarmupConfigName, tt.wantConfigName)
+			}
+			if len(config.Sources) != len(tt.wantSources) {
+				t.Errorf("Sources = %v, want %v", config.Sources, tt.wantSources)
+			}
+			for key, want := range tt.wantSources {
+				if config.Sources[key] != want {
+					t.Errorf("Sources[%s] = %q, want %q", key, config.Sources[key], want)
+				}
+			}
+			if got := config.DescribeSources(); got != tt.wantDescription {
+				t.Errorf("DescribeSources() = %q, want %q", got, tt.wantDescription)
+			}
+		})
+	}
+
+	t.Run("invalid namespace default is reported", func(t *testing.T) {
+		bad := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "team-a",
+			Annotations: map[string]string{webhook.AnnotationWarmupRequests: "lots"},
+		}}
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "team-a"}}
+		if _, err := ParseConfigWithDefaults(pod, webhook.Defaults{Namespace: bad}); err == nil || !strings.Contains(err.Error(), "warmup-requests") {
+			t.Errorf("ParseConfigWithDefaults() error = %v, want warmup-requests error", err)
+		}
+	})
+}
+
+func TestParseWarmupConfigRef(t *testing.T) {
+	tests := []struct {
+		ref      string
+		wantKind string
+		wantName string
+		wantErr  bool
+	}{
+		{ref: "my-warmup", wantKind: webhook.KindWarmupConfig, wantName: "my-warmup"},
+		{ref: "WarmupConfig/my-warmup", wantKind: webhook.KindWarmupConfig, wantName: "my-warmup"},
+		{ref: "ClusterWarmupConfig/jvm-spring", wantKind: webhook.KindClusterWarmupConfig, wantName: "jvm-spring"},
+		{ref: "ConfigMap/my-warmup", wantErr: true},
+		{ref: "ClusterWarmupConfig/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			kind, name, err := ParseWarmupConfigRef(tt.ref)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseWarmupConfigRef(%q) expected error, got (%q, %q)", tt.ref, kind, name)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWarmupConfigRef(%q) unexpected error: %v", tt.ref, err)
+			}
+			if kind != tt.wantKind || name != tt.wantName {
+				t.Errorf("ParseWarmupConfigRef(%q) = (%q, %q), want (%q, %q)", tt.ref, kind, name, tt.wantKind, tt.wantName)
+			}
+			config := &Config{WarmupConfigKind: kind, WarmupConfigName: name}
+			if want := strings.TrimPrefix(tt.ref, webhook.KindWarmupConfig+"/"); config.WarmupConfigRef() != want {
+				t.Errorf("WarmupConfigRef() = %q, want %q", config.WarmupConfigRef(), want)
+			}
+		})
+	}
+}
+
+func TestParseStages(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []Stage
+		wantErr bool
+	}{
+		{
+			name:  "single stage",
+			input: "10:30s",
+			want:  []Stage{{RPS: 10, Duration: 30 * time.Second}},
+		},
+		{
+			name:  "ramp with spaces and fractional rate",
+			input: "0.5:10s, 5:10s ,20:1m",
+			want: []Stage{
+				{RPS: 0.5, Duration: 10 * time.Second},
+				{RPS: 5, Duration: 10 * time.Second},
+				{RPS: 20, Duration: time.Minute},
+			},
+		},
+		{name: "missing duration", input: "10", wantErr: true},
+		{name: "zero rate", input: "0:10s", wantErr: true},
+		{name: "negative duration", input: "5:-1s", wantErr: true},
+		{name: "duration exceeds maximum", input: "5:20m", wantErr: true},
+		{name: "empty entry", input: "5:10s,,10:10s", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseStages(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStages(%q) expected error, got %v", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStages(%q) unexpected error: %v", tt.input, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ParseStages(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_BuildEndpointURL(t *testing.T) {
 	tests := []struct {
 		name   string
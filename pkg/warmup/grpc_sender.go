@@ -10,6 +10,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -19,6 +20,8 @@ import (
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 // GRPCSender executes a single gRPC unary warmup request using server reflection.
@@ -34,6 +37,7 @@ type GRPCSender struct {
 	reqMsg           *dynamicpb.Message            // cached after first successful reflection lookup
 	respMsg          *dynamicpb.Message            // cached after first successful reflection lookup
 	reflectionFailed bool                          // set on first failure; prevents re-attempting lookup
+	signer           *signing.Signer               // nil = requests are not signed
 }
 
 // NewGRPCSender creates a new GRPCSender.
@@ -49,6 +53,7 @@ func NewGRPCSender(logger logr.Logger) *GRPCSender {
 //   - Success → StatusCode 200, Error nil
 //   - Application-level gRPC error (non-OK status) → StatusCode 500, Error nil
 //     (latency is recorded; the application processed the request)
+//   - Status carries the call's gRPC code and message in both cases
 //   - Transport/reflection failure → Error non-nil (latency is not recorded)
 func (s *GRPCSender) Send(ctx context.Context, target Target) *Response {
 	start := time.Now()
@@ -94,28 +99,71 @@ func (s *GRPCSender) Send(ctx context.Context, target Target) *Response {
 		}
 	}
 
+	// Sign the call as "POST <method path>" with an empty body: the server receives the
+	// protobuf encoding rather than the JSON payload, so the payload cannot be covered.
+	callCtx := ctx
+	if s.signer != nil {
+		timestamp, signature := s.signer.Sign("POST", s.methodPath, nil)
+		callCtx = metadata.AppendToOutgoingContext(ctx,
+			strings.ToLower(signing.HeaderTimestamp), timestamp,
+			strings.ToLower(signing.HeaderSignature), signature)
+	}
+
 	// Invoke unary RPC; reset cached response message before each use.
 	proto.Reset(s.respMsg)
-	err := s.conn.Invoke(ctx, s.methodPath, s.reqMsg, s.respMsg)
+	err := s.conn.Invoke(callCtx, s.methodPath, s.reqMsg, s.respMsg)
 	duration := time.Since(start)
 
 	if err != nil {
-		st, _ := status.FromError(err)
 		// Context cancellation/deadline: signal the caller to stop the loop.
-		if st.Code() == codes.Canceled || st.Code() == codes.DeadlineExceeded {
+		if ctx.Err() != nil {
 			return &Response{Error: err, Duration: duration}
 		}
 		// Other gRPC errors: application processed the request; record latency, count as fail.
+		st := grpcStatus(err)
 		s.logger.V(2).Info("gRPC warmup request failed",
-			"method", target.Method, "code", st.Code(), "message", st.Message())
-		return &Response{StatusCode: 500, Duration: duration}
+			"method", target.Method, "code", st.Code, "message", st.Message)
+		return &Response{StatusCode: 500, Status: st, Duration: duration}
 	}
 
 	body, err := protojson.Marshal(s.respMsg)
 	if err != nil {
 		s.logger.V(2).Info("failed to marshal gRPC response body", "error", err)
 	}
-	return &Response{StatusCode: 200, Duration: duration, Body: body}
+	return &Response{StatusCode: 200, Status: &Status{Code: grpcCodeNames[codes.OK]}, Duration: duration, Body: body}
+}
+
+// grpcCodeNames are the canonical names of the gRPC status codes, as used by
+// expectedGRPCCode and in Status.
+var grpcCodeNames = [...]string{
+	codes.OK:                 "OK",
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// grpcStatus returns the Status of a gRPC error. Codes without a canonical name are
+// named by number, e.g. "CODE_20".
+func grpcStatus(err error) *Status {
+	st, _ := status.FromError(err)
+	code := fmt.Sprintf("CODE_%d", st.Code())
+	if int(st.Code()) < len(grpcCodeNames) {
+		code = grpcCodeNames[st.Code()]
+	}
+	return &Status{Code: code, Message: st.Message()}
 }
 
 // Close releases the underlying gRPC connection.
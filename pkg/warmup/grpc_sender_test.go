@@ -2,16 +2,22 @@ package warmup
 
 import (
 	"context"
+	"errors"
 	"net"
 	"strings"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 // startTestGRPCServer starts a gRPC server on a random local port and returns its address.
@@ -63,11 +69,64 @@ func TestGRPCSender_Send_Success(t *testing.T) {
 	if resp.StatusCode != 200 {
 		t.Errorf("Send() StatusCode = %d, want 200", resp.StatusCode)
 	}
+	if resp.Status == nil || resp.Status.Code != "OK" {
+		t.Errorf("Send() Status = %+v, want OK", resp.Status)
+	}
 	if resp.Duration == 0 {
 		t.Error("Send() Duration = 0, want > 0")
 	}
 }
 
+func TestGRPCSender_Send_ErrorStatus(t *testing.T) {
+	addr, stop := startTestGRPCServer(t, true)
+	defer stop()
+
+	sender := NewGRPCSender(ctrl.Log.WithName("test"))
+	t.Cleanup(func() { sender.Close() }) //nolint:errcheck
+
+	// The health server answers NotFound for unknown services
+	resp := sender.Send(context.Background(), Target{
+		Address: addr,
+		Method:  "grpc.health.v1.Health/Check",
+		Payload: []byte(`{"service":"unknown"}`),
+	})
+
+	if resp.Error != nil {
+		t.Fatalf("Send() unexpected error: %v", resp.Error)
+	}
+	if resp.StatusCode != 500 {
+		t.Errorf("Send() StatusCode = %d, want 500", resp.StatusCode)
+	}
+	if resp.Status == nil || resp.Status.Code != "NOT_FOUND" || resp.Status.Message == "" {
+		t.Errorf("Send() Status = %+v, want NOT_FOUND with a message", resp.Status)
+	}
+	if got := resp.StatusName(); got != "NOT_FOUND" {
+		t.Errorf("StatusName() = %q, want NOT_FOUND", got)
+	}
+	if got := ClassifyResponse(resp, false); got != "grpc_not_found" {
+		t.Errorf("ClassifyResponse() = %q, want grpc_not_found", got)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Status
+	}{
+		{err: status.Error(codes.Unavailable, "connection refused"), want: Status{Code: "UNAVAILABLE", Message: "connection refused"}},
+		{err: status.Error(codes.Canceled, ""), want: Status{Code: "CANCELLED"}},
+		{err: status.Error(codes.PermissionDenied, "denied"), want: Status{Code: "PERMISSION_DENIED", Message: "denied"}},
+		{err: status.Error(codes.Code(20), ""), want: Status{Code: "CODE_20"}},
+		{err: errors.New("boom"), want: Status{Code: "UNKNOWN", Message: "boom"}},
+	}
+
+	for _, tt := range tests {
+		if got := grpcStatus(tt.err); *got != tt.want {
+			t.Errorf("grpcStatus(%v) = %+v, want %+v", tt.err, *got, tt.want)
+		}
+	}
+}
+
 func TestGRPCSender_Send_ReflectionUnavailable(t *testing.T) {
 	addr, stop := startTestGRPCServer(t, false) // no reflection
 	defer stop()
@@ -174,6 +233,51 @@ func TestGRPCSender_Send_ConnectionReuse(t *testing.T) {
 	}
 }
 
+func TestGRPCSender_Send_Signed(t *testing.T) {
+	key := []byte("grpc-key")
+	verifier := signing.NewVerifier(key, 0)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	var verifyErr error
+	srv := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any,
+		info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		first := func(k string) string {
+			if v := md.Get(k); len(v) > 0 {
+				return v[0]
+			}
+			return ""
+		}
+		verifyErr = verifier.Verify("POST", info.FullMethod, nil,
+			first(signing.HeaderTimestamp), first(signing.HeaderSignature))
+		return handler(ctx, req)
+	}))
+	healthSrv := health.NewServer()
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	reflection.Register(srv)
+	go srv.Serve(lis) //nolint:errcheck
+	defer srv.GracefulStop()
+
+	sender := NewGRPCSender(ctrl.Log.WithName("test"))
+	sender.signer = signing.NewSigner(key)
+	t.Cleanup(func() { sender.Close() }) //nolint:errcheck
+
+	resp := sender.Send(context.Background(), Target{
+		Address: lis.Addr().String(),
+		Method:  "grpc.health.v1.Health/Check",
+		Payload: []byte(`{}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("Send() unexpected error: %v", resp.Error)
+	}
+	if verifyErr != nil {
+		t.Errorf("server could not verify signed call: %v", verifyErr)
+	}
+}
+
 func TestGRPCSender_Send_ContextCancellation(t *testing.T) {
 	addr, stop := startTestGRPCServer(t, true)
 	defer stop()
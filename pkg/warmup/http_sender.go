@@ -8,17 +8,21 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 // HTTPSender executes a single HTTP warmup request.
 type HTTPSender struct {
 	client *http.Client
 	logger logr.Logger
+	signer *signing.Signer // nil = requests are not signed
 }
 
 // Send issues one HTTP request to target.Address using target.Method (default GET)
 // with optional target.Payload as the request body. Response body is captured and
 // returned in Response.Body so callers can extract values for session chaining.
+// When a signer is configured, signature headers are added after target.Headers.
 func (s *HTTPSender) Send(ctx context.Context, target Target) *Response {
 	start := time.Now()
 
@@ -40,6 +44,9 @@ func (s *HTTPSender) Send(ctx context.Context, target Target) *Response {
 	for k, v := range target.Headers {
 		req.Header.Set(k, v)
 	}
+	for k, v := range s.signer.Headers(method, req.URL.RequestURI(), target.Payload) {
+		req.Header.Set(k, v)
+	}
 
 	resp, err := s.client.Do(req)
 	duration := time.Since(start)
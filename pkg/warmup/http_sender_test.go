@@ -9,6 +9,8 @@ import (
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 func TestHTTPSender_Send(t *testing.T) {
@@ -174,3 +176,34 @@ func TestHTTPSender_Send_ConnectionError(t *testing.T) {
 		t.Error("Send() expected connection error, got nil")
 	}
 }
+
+func TestHTTPSender_Send_Signed(t *testing.T) {
+	logger := ctrl.Log.WithName("test")
+	key := []byte("warmup-key")
+	verifier := signing.NewVerifier(key, 0)
+
+	var verifyErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		verifyErr = verifier.VerifyRequest(r)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := &HTTPSender{
+		client: &http.Client{Timeout: 5 * time.Second},
+		logger: logger,
+		signer: signing.NewSigner(key),
+	}
+
+	resp := sender.Send(context.Background(), Target{
+		Address: server.URL + "/api/warmup?mode=full",
+		Method:  http.MethodPost,
+		Payload: []byte(`{"action":"preload"}`),
+	})
+	if resp.Error != nil {
+		t.Fatalf("Send() unexpected error: %v", resp.Error)
+	}
+	if verifyErr != nil {
+		t.Errorf("server could not verify signed request: %v", verifyErr)
+	}
+}
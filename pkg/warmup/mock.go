@@ -32,11 +32,13 @@ var _ Executor = (*MockExecutor)(nil)
 type MockScenarioExecutor struct {
 	Result *Result
 	Called bool
+	Config *Config // config of the last call
 }
 
 // ExecuteScenario records the call and returns the configured Result.
-func (m *MockScenarioExecutor) ExecuteScenario(_ context.Context, _ *Config, _ *v1alpha1.WarmupConfigSpec) *Result {
+func (m *MockScenarioExecutor) ExecuteScenario(_ context.Context, config *Config, _ *v1alpha1.WarmupConfigSpec) *Result {
 	m.Called = true
+	m.Config = config
 	if m.Result != nil {
 		return m.Result
 	}
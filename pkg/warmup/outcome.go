@@ -0,0 +1,94 @@
+package warmup
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"google.golang.org/grpc/status"
+)
+
+// Outcome classes of warmup responses (see ClassifyResponse). HTTP responses that
+// do not count as completed are classified as "http_<n>xx", and other gRPC
+// statuses as "grpc_<code>", e.g. "grpc_unavailable".
+const (
+	OutcomeSuccess      = "success"
+	OutcomeRateLimited  = "rate_limited"
+	OutcomeTimeout      = "timeout"
+	OutcomeConnRefused  = "conn_refused"
+	OutcomeDNS          = "dns"
+	OutcomeTLS          = "tls"
+	OutcomeCancelled    = "cancelled"
+	OutcomeOtherFailure = "error"
+)
+
+// ClassifyResponse returns the outcome class of resp, which counts as completed if
+// ok. Transport failures are classified by their cause; failures that match no
+// class are OutcomeOtherFailure.
+func ClassifyResponse(resp *Response, ok bool) string {
+	if resp.Error != nil {
+		return classifyError(resp.Error)
+	}
+	if ok {
+		return OutcomeSuccess
+	}
+	if resp.Status != nil {
+		return classifyStatus(resp.Status)
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return OutcomeRateLimited
+	}
+	return fmt.Sprintf("http_%dxx", resp.StatusCode/100)
+}
+
+// classifyError returns the outcome class of a request that failed with err.
+func classifyError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var certErr *tls.CertificateVerificationError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return OutcomeTimeout
+	case errors.Is(err, context.Canceled):
+		return OutcomeCancelled
+	case errors.As(err, &dnsErr):
+		return OutcomeDNS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return OutcomeConnRefused
+	case errors.As(err, &certErr), errors.As(err, new(tls.RecordHeaderError)), errors.As(err, new(tls.AlertError)),
+		errors.As(err, new(x509.UnknownAuthorityError)), errors.As(err, new(x509.HostnameError)),
+		errors.As(err, new(x509.CertificateInvalidError)):
+		return OutcomeTLS
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return OutcomeTimeout
+	}
+
+	// gRPC calls and reflection lookups fail with a status
+	if _, ok := status.FromError(err); ok {
+		return classifyStatus(grpcStatus(err))
+	}
+	return OutcomeOtherFailure
+}
+
+// classifyStatus returns the outcome class of a gRPC call that did not count as
+// completed. The gRPC client reports connection failures as UNAVAILABLE, with the
+// cause in the message.
+func classifyStatus(st *Status) string {
+	switch st.Code {
+	case "DEADLINE_EXCEEDED":
+		return OutcomeTimeout
+	case "CANCELLED":
+		return OutcomeCancelled
+	case "UNAVAILABLE":
+		if strings.Contains(st.Message, "connection refused") {
+			return OutcomeConnRefused
+		}
+	}
+	return "grpc_" + strings.ToLower(st.Code)
+}
@@ -0,0 +1,74 @@
+package warmup
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// timeoutError is a net.Error that timed out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyResponse(t *testing.T) {
+	urlError := func(err error) error {
+		return &url.Error{Op: "Get", URL: "http://10.0.0.1:8080/", Err: err}
+	}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+
+	tests := []struct {
+		name string
+		resp *Response
+		ok   bool
+		want string
+	}{
+		{name: "HTTP success", resp: &Response{StatusCode: 200}, ok: true, want: OutcomeSuccess},
+		{name: "expected 404", resp: &Response{StatusCode: 404}, ok: true, want: OutcomeSuccess},
+		{name: "HTTP 404", resp: &Response{StatusCode: 404}, want: "http_4xx"},
+		{name: "HTTP 503", resp: &Response{StatusCode: 503}, want: "http_5xx"},
+		{name: "unexpected 200", resp: &Response{StatusCode: 200}, want: "http_2xx"},
+		{name: "HTTP 429", resp: &Response{StatusCode: 429}, want: OutcomeRateLimited},
+		{name: "gRPC OK", resp: &Response{StatusCode: 200, Status: &Status{Code: "OK"}}, ok: true, want: OutcomeSuccess},
+		{name: "unexpected gRPC OK", resp: &Response{StatusCode: 200, Status: &Status{Code: "OK"}}, want: "grpc_ok"},
+		{
+			name: "gRPC error",
+			resp: &Response{StatusCode: 500, Status: &Status{Code: "RESOURCE_EXHAUSTED", Message: "quota"}},
+			want: "grpc_resource_exhausted",
+		},
+		{
+			name: "gRPC connection refused",
+			resp: &Response{StatusCode: 500, Status: &Status{Code: "UNAVAILABLE", Message: "connection error: dial tcp: connect: connection refused"}},
+			want: OutcomeConnRefused,
+		},
+		{name: "gRPC server deadline", resp: &Response{StatusCode: 500, Status: &Status{Code: "DEADLINE_EXCEEDED"}}, want: OutcomeTimeout},
+		{name: "gRPC deadline", resp: &Response{Error: status.Error(codes.DeadlineExceeded, "deadline")}, want: OutcomeTimeout},
+		{name: "gRPC reflection", resp: &Response{Error: fmt.Errorf("reflection: %w", status.Error(codes.Unimplemented, "no reflection"))}, want: "grpc_unimplemented"},
+		{name: "deadline", resp: &Response{Error: urlError(context.DeadlineExceeded)}, want: OutcomeTimeout},
+		{name: "client timeout", resp: &Response{Error: urlError(timeoutError{})}, want: OutcomeTimeout},
+		{name: "cancelled", resp: &Response{Error: urlError(context.Canceled)}, want: OutcomeCancelled},
+		{name: "connection refused", resp: &Response{Error: urlError(refused)}, want: OutcomeConnRefused},
+		{name: "DNS", resp: &Response{Error: urlError(&net.DNSError{Err: "no such host", Name: "svc"})}, want: OutcomeDNS},
+		{name: "TLS", resp: &Response{Error: urlError(x509.UnknownAuthorityError{})}, want: OutcomeTLS},
+		{name: "other", resp: &Response{Error: errors.New("invalid gRPC payload")}, want: OutcomeOtherFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ClassifyResponse(tt.resp, tt.ok); got != tt.want {
+				t.Errorf("ClassifyResponse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package warmup
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+// ParamVariablePrefix prefixes the session variables that hold WarmupConfig
+// parameters, so that requests reference them as {{params.<name>}}.
+const ParamVariablePrefix = "params."
+
+// ParseParams returns the WarmupConfig parameters set in annotations: the entries
+// of the kube-booster.io/warmup-config-params JSON object, overridden by individual
+// kube-booster.io/param.<name> annotations. JSON values must be strings, numbers or
+// booleans. It returns nil if no parameter is set.
+func ParseParams(annotations map[string]string) (map[string]string, error) {
+	var params map[string]string
+	set := func(name, value string) {
+		if params == nil {
+			params = map[string]string{}
+		}
+		params[name] = value
+	}
+
+	if raw := annotations[webhook.AnnotationWarmupConfigParams]; raw != "" {
+		decoder := json.NewDecoder(strings.NewReader(raw))
+		decoder.UseNumber()
+		var values map[string]any
+		if err := decoder.Decode(&values); err != nil {
+			return nil, fmt.Errorf("invalid %s value: must be a JSON object: %w", webhook.AnnotationWarmupConfigParams, err)
+		}
+		for name, v := range values {
+			switch v := v.(type) {
+			case string:
+				set(name, v)
+			case json.Number, bool:
+				set(name, fmt.Sprintf("%v", v))
+			default:
+				return nil, fmt.Errorf("invalid %s value: parameter %q must be a string, number or boolean",
+					webhook.AnnotationWarmupConfigParams, name)
+			}
+		}
+	}
+
+	for key, value := range annotations {
+		if name, ok := strings.CutPrefix(key, webhook.AnnotationWarmupParamPrefix); ok && name != "" {
+			set(name, value)
+		}
+	}
+	return params, nil
+}
+
+// ResolveParams returns the value of every parameter declared by spec: the value in
+// values if set, the parameter's default otherwise. Parameters in values that spec
+// does not declare are dropped. It fails, naming every missing parameter, if a
+// required parameter is not set.
+func ResolveParams(spec *v1alpha1.WarmupConfigSpec, values map[string]string) (map[string]string, error) {
+	if len(spec.Parameters) == 0 {
+		return nil, nil
+	}
+	resolved := make(map[string]string, len(spec.Parameters))
+	var missing []string
+	for _, p := range spec.Parameters {
+		if v, ok := values[p.Name]; ok {
+			resolved[p.Name] = v
+			continue
+		}
+		if p.Required {
+			missing = append(missing, p.Name)
+			continue
+		}
+		resolved[p.Name] = p.Default
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("required parameters not set: %s (set %s<name> or %s on the pod)",
+			strings.Join(missing, ", "), webhook.AnnotationWarmupParamPrefix, webhook.AnnotationWarmupConfigParams)
+	}
+	return resolved, nil
+}
@@ -0,0 +1,134 @@
+package warmup
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestParseParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        map[string]string
+		wantErr     bool
+	}{
+		{
+			name:        "none",
+			annotations: map[string]string{webhook.AnnotationWarmupEndpoint: "/warmup"},
+			want:        nil,
+		},
+		{
+			name:        "JSON object",
+			annotations: map[string]string{webhook.AnnotationWarmupConfigParams: `{"tenant":"acme","pageSize":50,"cache":true}`},
+			want:        map[string]string{"tenant": "acme", "pageSize": "50", "cache": "true"},
+		},
+		{
+			name:        "individual annotations",
+			annotations: map[string]string{"kube-booster.io/param.tenant": "acme"},
+			want:        map[string]string{"tenant": "acme"},
+		},
+		{
+			name: "individual annotation overrides JSON",
+			annotations: map[string]string{
+				webhook.AnnotationWarmupConfigParams: `{"tenant":"acme","region":"eu"}`,
+				"kube-booster.io/param.tenant":       "globex",
+			},
+			want: map[string]string{"tenant": "globex", "region": "eu"},
+		},
+		{
+			name:        "empty name ignored",
+			annotations: map[string]string{"kube-booster.io/param.": "x"},
+			want:        nil,
+		},
+		{
+			name:        "invalid JSON",
+			annotations: map[string]string{webhook.AnnotationWarmupConfigParams: `tenant=acme`},
+			wantErr:     true,
+		},
+		{
+			name:        "nested value",
+			annotations: map[string]string{webhook.AnnotationWarmupConfigParams: `{"tenant":{"id":1}}`},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseParams(tt.annotations)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseParams() expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseParams() unexpected error: %v", err)
+			}
+			if !maps.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("ParseParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveParams(t *testing.T) {
+	spec := &v1alpha1.WarmupConfigSpec{
+		Parameters: []v1alpha1.WarmupParameter{
+			{Name: "tenant", Required: true},
+			{Name: "region", Default: "us"},
+			{Name: "account", Required: true},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		values      map[string]string
+		want        map[string]string
+		wantMissing []string
+	}{
+		{
+			name:   "defaults applied and undeclared dropped",
+			values: map[string]string{"tenant": "acme", "account": "42", "typo": "x"},
+			want:   map[string]string{"tenant": "acme", "account": "42", "region": "us"},
+		},
+		{
+			name:   "value overrides default",
+			values: map[string]string{"tenant": "acme", "account": "42", "region": "eu"},
+			want:   map[string]string{"tenant": "acme", "account": "42", "region": "eu"},
+		},
+		{
+			name:        "missing required",
+			values:      map[string]string{"region": "eu"},
+			wantMissing: []string{"tenant", "account"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveParams(spec, tt.values)
+			if tt.wantMissing != nil {
+				if err == nil {
+					t.Fatalf("ResolveParams() expected error, got %v", got)
+				}
+				if want := strings.Join(tt.wantMissing, ", "); !strings.Contains(err.Error(), want) {
+					t.Errorf("ResolveParams() error = %q, want it to name %q", err, want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveParams() unexpected error: %v", err)
+			}
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("ResolveParams() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got, err := ResolveParams(&v1alpha1.WarmupConfigSpec{}, map[string]string{"tenant": "acme"}); err != nil || got != nil {
+		t.Errorf("ResolveParams() without declared parameters = (%v, %v), want (nil, nil)", got, err)
+	}
+}
@@ -0,0 +1,60 @@
+package warmup
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+// Progress is the state of a pod's warmup that a controller instance hands off to
+// the next one when it shuts down before the warmup has finished. It is stored as
+// JSON in the kube-booster.io/warmup-progress annotation.
+type Progress struct {
+	// Attempt is the number of warmup attempts that have been started for the pod.
+	Attempt int `json:"attempt"`
+
+	// Steps lists the names of the WarmupConfig steps that have completed, in order.
+	Steps []string `json:"steps,omitempty"`
+
+	// Variables holds the session variables extracted by the completed steps that
+	// the WarmupConfig allows to be persisted (spec.persistVariables).
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+// ParseProgress reads the progress annotation from pod. It returns nil if the
+// annotation is not set.
+func ParseProgress(pod *corev1.Pod) (*Progress, error) {
+	value, ok := pod.Annotations[webhook.AnnotationWarmupProgress]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	p := &Progress{}
+	if err := json.Unmarshal([]byte(value), p); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", webhook.AnnotationWarmupProgress, err)
+	}
+	if p.Attempt < 0 {
+		return nil, fmt.Errorf("invalid %s annotation: attempt must not be negative", webhook.AnnotationWarmupProgress)
+	}
+	return p, nil
+}
+
+// NextAttempt returns the attempt number for a warmup started after p.
+// A nil receiver means no earlier attempt was recorded.
+func (p *Progress) NextAttempt() int {
+	if p == nil {
+		return 1
+	}
+	return p.Attempt + 1
+}
+
+// Annotation returns p encoded as the value of the progress annotation.
+func (p *Progress) Annotation() (string, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
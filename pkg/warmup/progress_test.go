@@ -0,0 +1,61 @@
+package warmup
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestParseProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		wantNext    int
+		wantErr     bool
+	}{
+		{name: "no annotation", wantNext: 1},
+		{name: "one interrupted attempt", annotations: map[string]string{webhook.AnnotationWarmupProgress: `{"attempt":1}`}, wantNext: 2},
+		{name: "invalid JSON", annotations: map[string]string{webhook.AnnotationWarmupProgress: `attempt=1`}, wantErr: true},
+		{name: "negative attempt", annotations: map[string]string{webhook.AnnotationWarmupProgress: `{"attempt":-1}`}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got, err := ParseProgress(pod)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseProgress() expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseProgress() unexpected error: %v", err)
+			}
+			if next := got.NextAttempt(); next != tt.wantNext {
+				t.Errorf("NextAttempt() = %d, want %d", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestProgress_Annotation(t *testing.T) {
+	want := &Progress{Attempt: 2, Steps: []string{"login"}, Variables: map[string]any{"region": "eu"}}
+	value, err := want.Annotation()
+	if err != nil {
+		t.Fatalf("Annotation() error = %v", err)
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		webhook.AnnotationWarmupProgress: value,
+	}}}
+	got, err := ParseProgress(pod)
+	if err != nil {
+		t.Fatalf("ParseProgress(Annotation()) error = %v", err)
+	}
+	if got.Attempt != 2 || len(got.Steps) != 1 || got.Steps[0] != "login" || got.Variables["region"] != "eu" {
+		t.Errorf("ParseProgress(Annotation()) = %+v, want %+v", got, want)
+	}
+}
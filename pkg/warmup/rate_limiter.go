@@ -2,7 +2,10 @@ package warmup
 
 import (
 	"context"
+	"errors"
 	"math"
+	"sync"
+	"time"
 
 	"golang.org/x/time/rate"
 )
@@ -37,3 +40,116 @@ func (r *RequestRateLimiter) Wait(ctx context.Context) error {
 	}
 	return r.limiter.Wait(ctx)
 }
+
+// waitAll waits on each limiter in order, stopping at the first error. Per-execution
+// limiters should come before the shared one so that a throttled pod does not hold
+// global tokens it cannot yet use.
+func waitAll(ctx context.Context, limiters ...*RequestRateLimiter) error {
+	for _, l := range limiters {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// minRPS returns the lowest positive rate among rates, or 0 if none is set.
+func minRPS(rates ...float64) float64 {
+	var lowest float64
+	for _, r := range rates {
+		if r > 0 && (lowest == 0 || r < lowest) {
+			lowest = r
+		}
+	}
+	return lowest
+}
+
+// Stage is a period of constant request rate within a ramp-up schedule.
+type Stage struct {
+	// RPS is the request rate during the stage.
+	RPS float64
+
+	// Duration is how long the stage lasts.
+	Duration time.Duration
+}
+
+// ErrStagesComplete is returned by StageRateLimiter.Wait once the final stage has ended.
+var ErrStagesComplete = errors.New("load stages complete")
+
+// StageRateLimiter paces a single warmup execution through a sequence of load stages
+// (e.g. 5 rps for 10s, then 20 rps for 10s). The schedule starts on the first Wait
+// call. It is meant to be layered under the shared RequestRateLimiter: callers wait
+// on the stage limiter first, then on the global one.
+// A nil receiver is valid and means no staging is applied.
+type StageRateLimiter struct {
+	mu      sync.Mutex
+	stages  []Stage
+	limiter *rate.Limiter
+	current int
+	start   time.Time
+	now     func() time.Time
+}
+
+// NewStageRateLimiter returns a StageRateLimiter for stages, or nil if stages is empty.
+func NewStageRateLimiter(stages []Stage) *StageRateLimiter {
+	if len(stages) == 0 {
+		return nil
+	}
+	// Burst 1 spreads requests evenly across each stage instead of front-loading them.
+	return &StageRateLimiter{
+		stages:  stages,
+		limiter: rate.NewLimiter(rate.Limit(stages[0].RPS), 1),
+		now:     time.Now,
+	}
+}
+
+// Wait blocks until the current stage permits another request, or ctx is done.
+// It returns ErrStagesComplete once the final stage has ended.
+// A nil receiver is a no-op and returns nil immediately.
+func (s *StageRateLimiter) Wait(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.advance(); err != nil {
+		return err
+	}
+	if err := s.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	// The final stage may have ended while waiting for the token.
+	return s.advance()
+}
+
+// advance switches the limiter to the stage active at the current time.
+func (s *StageRateLimiter) advance() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.start.IsZero() {
+		s.start = now
+	}
+	elapsed := now.Sub(s.start)
+
+	var end time.Duration
+	for i, stage := range s.stages {
+		end += stage.Duration
+		if elapsed < end {
+			if i != s.current {
+				s.current = i
+				s.limiter.SetLimitAt(now, rate.Limit(stage.RPS))
+			}
+			return nil
+		}
+	}
+	return ErrStagesComplete
+}
+
+// TotalStageDuration returns the combined duration of all stages.
+func TotalStageDuration(stages []Stage) time.Duration {
+	var total time.Duration
+	for _, stage := range stages {
+		total += stage.Duration
+	}
+	return total
+}
@@ -2,6 +2,7 @@ package warmup
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 )
@@ -132,3 +133,114 @@ func TestRequestRateLimiter_ContextCancellation(t *testing.T) {
 		t.Error("Wait() with cancelled context should return error")
 	}
 }
+
+func TestStageRateLimiter_NilReceiver(t *testing.T) {
+	if sl := NewStageRateLimiter(nil); sl != nil {
+		t.Fatal("NewStageRateLimiter(nil) should return nil")
+	}
+	var sl *StageRateLimiter
+	if err := sl.Wait(context.Background()); err != nil {
+		t.Errorf("nil StageRateLimiter.Wait() = %v, want nil", err)
+	}
+}
+
+func TestStageRateLimiter_AdvancesAndCompletes(t *testing.T) {
+	sl := NewStageRateLimiter([]Stage{
+		{RPS: 1000, Duration: 10 * time.Second},
+		{RPS: 2000, Duration: 10 * time.Second},
+	})
+	clock := time.Unix(0, 0)
+	sl.now = func() time.Time { return clock }
+
+	if err := sl.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() in first stage error = %v", err)
+	}
+	if sl.current != 0 {
+		t.Errorf("current stage = %d, want 0", sl.current)
+	}
+
+	clock = clock.Add(15 * time.Second)
+	if err := sl.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() in second stage error = %v", err)
+	}
+	if sl.current != 1 || sl.limiter.Limit() != 2000 {
+		t.Errorf("current stage = %d (limit %v), want 1 (limit 2000)", sl.current, sl.limiter.Limit())
+	}
+
+	clock = clock.Add(10 * time.Second)
+	if err := sl.Wait(context.Background()); !errors.Is(err, ErrStagesComplete) {
+		t.Errorf("Wait() after final stage error = %v, want %v", err, ErrStagesComplete)
+	}
+}
+
+func TestStageRateLimiter_Pacing(t *testing.T) {
+	sl := NewStageRateLimiter([]Stage{{RPS: 20, Duration: 200 * time.Millisecond}})
+
+	var n int
+	start := time.Now()
+	for {
+		err := sl.Wait(context.Background())
+		if errors.Is(err, ErrStagesComplete) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+		n++
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("stages finished after %v, want >= 200ms", elapsed)
+	}
+	// 20 rps over 200ms allows about 4 requests plus the initial burst token.
+	if n < 3 || n > 6 {
+		t.Errorf("requests permitted = %d, want about 4", n)
+	}
+}
+
+func TestTotalStageDuration(t *testing.T) {
+	got := TotalStageDuration([]Stage{{RPS: 1, Duration: 10 * time.Second}, {RPS: 5, Duration: 5 * time.Second}})
+	if got != 15*time.Second {
+		t.Errorf("TotalStageDuration() = %v, want 15s", got)
+	}
+}
+
+func TestMinRPS(t *testing.T) {
+	tests := []struct {
+		name  string
+		rates []float64
+		want  float64
+	}{
+		{name: "none set", rates: []float64{0, 0}, want: 0},
+		{name: "only first set", rates: []float64{2.5, 0}, want: 2.5},
+		{name: "only second set", rates: []float64{0, 10}, want: 10},
+		{name: "lower wins", rates: []float64{20, 5}, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := minRPS(tt.rates...); got != tt.want {
+				t.Errorf("minRPS(%v) = %v, want %v", tt.rates, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWaitAll_RespectsEachLimiter(t *testing.T) {
+	// The slow per-execution limiter must throttle even though the shared one is generous.
+	slow := NewRequestRateLimiter(2)
+	fast := NewRequestRateLimiter(1000)
+
+	start := time.Now()
+	for range 4 {
+		if err := waitAll(context.Background(), slow, fast); err != nil {
+			t.Fatalf("waitAll() error = %v", err)
+		}
+	}
+	// Burst of 2, then two more tokens at 2 rps.
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("4 waits finished after %v, want >= ~1s", elapsed)
+	}
+
+	if err := waitAll(context.Background(), nil, nil); err != nil {
+		t.Errorf("waitAll() with nil limiters error = %v", err)
+	}
+}
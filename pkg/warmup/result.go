@@ -2,6 +2,9 @@ package warmup
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -25,17 +28,195 @@ type Result struct {
 	// LatencyP99 is the 99th percentile latency
 	LatencyP99 time.Duration
 
+	// FirstRequestLatency is the latency of the first response, which usually pays
+	// for the application's cold start
+	FirstRequestLatency time.Duration
+
+	// FinalWindowLatency is the mean latency of the last responses (see
+	// finalWindowPercent), once the application has warmed up
+	FinalWindowLatency time.Duration
+
+	// SpeedupRatio is FirstRequestLatency divided by FinalWindowLatency: how much
+	// faster the application answered at the end of the warmup than at its start.
+	// It is 0 when fewer than two responses were received.
+	SpeedupRatio float64
+
+	// Outcomes counts the requests that were sent by outcome class (see
+	// ClassifyResponse). Requests that were never sent are not included.
+	Outcomes map[string]int
+
 	// Error contains any error that occurred during warmup
 	Error error
 
 	// Message is a human-readable summary of the warmup result
 	Message string
+
+	// Progress records the scenario steps that completed and the variables they
+	// extracted, so that an interrupted scenario can be resumed. Attempt is left
+	// for the caller to fill in. It is nil for single-endpoint warmups.
+	Progress *Progress
+
+	// Steps reports the outcome of each scenario step that ran, in order. Steps
+	// skipped on resume or not reached before the scenario timeout are not included.
+	// It is nil for single-endpoint warmups.
+	Steps []StepResult
+}
+
+// StepResult is the outcome of a single WarmupConfig step.
+type StepResult struct {
+	// Name is the step's name (see StepName).
+	Name string
+
+	// RequestsCompleted is the number of the step's requests that succeeded
+	RequestsCompleted int
+
+	// RequestsFailed is the number of the step's requests that failed
+	RequestsFailed int
+
+	// Duration is the time taken by the step
+	Duration time.Duration
+
+	// LatencyP50 and LatencyP99 are the latency percentiles of the step's responses
+	LatencyP50 time.Duration
+	LatencyP99 time.Duration
+
+	// Requests reports the outcome of each of the step's requests, in spec order.
+	// Requests not reached before the step ended are not included; for a mix, every
+	// request is included.
+	Requests []RequestResult
+}
+
+// RequestResult is the outcome of a single request of a WarmupConfig step, over
+// all the times it was sent.
+type RequestResult struct {
+	// Name is the request's name, or "<step>/req-<n>" if it has none.
+	Name string
+
+	// RequestsCompleted is the number of times the request succeeded
+	RequestsCompleted int
+
+	// RequestsFailed is the number of times the request failed
+	RequestsFailed int
+
+	// LatencyP50 and LatencyP99 are the latency percentiles of the request's
+	// responses, whatever their status
+	LatencyP50 time.Duration
+	LatencyP99 time.Duration
+
+	// FirstLatency is the latency of the first response to the request, which
+	// usually pays for the application's cold start
+	FirstLatency time.Duration
+
+	// StatusCodes counts the request's responses by status name (see
+	// Response.StatusName), e.g. "503" or "UNAVAILABLE". Requests that got no
+	// response are not counted; see Errors.
+	StatusCodes map[string]int
+
+	// Errors lists the distinct errors of the requests that got no response, up to
+	// maxRequestErrors, in the order they first occurred
+	Errors []string
+
+	// Outcomes counts the times the request was sent by outcome class (see
+	// ClassifyResponse)
+	Outcomes map[string]int
+}
+
+// maxRequestErrors caps the number of distinct errors kept per request.
+const maxRequestErrors = 3
+
+const (
+	// finalWindowPercent is the share of the responses, after the first, whose mean
+	// latency is FinalWindowLatency. The window holds at least one response.
+	finalWindowPercent = 10
+
+	// maxMessageRequests caps the number of failed requests named in a result message.
+	maxMessageRequests = 3
+
+	// maxMessageError caps the length of a request error quoted in a result message,
+	// which also appears in events.
+	maxMessageError = 120
+)
+
+// Failed reports whether any of the step's requests failed.
+func (s StepResult) Failed() bool {
+	return s.RequestsFailed > 0
+}
+
+// succeeded reports whether the step met its success criteria: at least one of its
+// requests succeeded, or it had nothing to send.
+func (s StepResult) succeeded() bool {
+	return s.RequestsCompleted > 0 || s.RequestsFailed == 0
+}
+
+// Describe summarizes the request's failures, e.g. "2/5 failed, status 200×3 503×2"
+// or "2/5 failed, status OK×3 UNAVAILABLE×2".
+func (r RequestResult) Describe() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d/%d failed", r.RequestsFailed, r.RequestsCompleted+r.RequestsFailed)
+	if len(r.StatusCodes) > 0 {
+		b.WriteString(", status")
+		for _, code := range slices.Sorted(maps.Keys(r.StatusCodes)) {
+			fmt.Fprintf(&b, " %s×%d", code, r.StatusCodes[code])
+		}
+	}
+	if len(r.Errors) > 0 {
+		msg := r.Errors[0]
+		if len(msg) > maxMessageError {
+			msg = msg[:maxMessageError-3] + "..."
+		}
+		fmt.Fprintf(&b, ", error: %s", msg)
+	}
+	return b.String()
+}
+
+// measureEffectiveness sets the first-request latency, the final-window latency and
+// the speedup ratio from latencies, the latencies of the responses in the order the
+// requests were sent.
+func (r *Result) measureEffectiveness(latencies []time.Duration) {
+	if len(latencies) == 0 {
+		return
+	}
+	r.FirstRequestLatency = latencies[0]
+	rest := latencies[1:]
+	if len(rest) == 0 {
+		return
+	}
+	window := rest[len(rest)-max(1, len(rest)*finalWindowPercent/100):]
+	var total time.Duration
+	for _, d := range window {
+		total += d
+	}
+	r.FinalWindowLatency = total / time.Duration(len(window))
+	if r.FinalWindowLatency > 0 {
+		r.SpeedupRatio = float64(r.FirstRequestLatency) / float64(r.FinalWindowLatency)
+	}
+}
+
+// countOutcomes counts n requests sent with the outcome class.
+func (r *Result) countOutcomes(class string, n int) {
+	if r.Outcomes == nil {
+		r.Outcomes = make(map[string]int)
+	}
+	r.Outcomes[class] += n
+}
+
+// FailedRequests returns the requests of all steps that had failures, in order.
+func (r *Result) FailedRequests() []RequestResult {
+	var failed []RequestResult
+	for _, step := range r.Steps {
+		for _, req := range step.Requests {
+			if req.RequestsFailed > 0 {
+				failed = append(failed, req)
+			}
+		}
+	}
+	return failed
 }
 
 // BuildMessage creates a human-readable summary of the warmup result
 func (r *Result) BuildMessage() string {
 	if r.Error != nil {
-		return fmt.Sprintf("warmup failed: %v", r.Error)
+		return fmt.Sprintf("warmup failed: %v", r.Error) + r.failedRequestsSuffix()
 	}
 
 	if r.RequestsCompleted == 0 && r.RequestsFailed == 0 {
@@ -51,11 +232,37 @@ func (r *Result) BuildMessage() string {
 			successRate,
 			r.TotalDuration.Round(time.Millisecond),
 			r.LatencyP50,
-			r.LatencyP99)
+			r.LatencyP99) + r.speedupSuffix() + r.failedRequestsSuffix()
 	}
 
 	return fmt.Sprintf("warmup completed with failures: %d/%d requests succeeded (%.1f%%)",
 		r.RequestsCompleted,
 		r.RequestsCompleted+r.RequestsFailed,
-		successRate)
+		successRate) + r.failedRequestsSuffix()
+}
+
+// speedupSuffix reports the cold-start penalty for the result message, if measured.
+func (r *Result) speedupSuffix() string {
+	if r.SpeedupRatio == 0 {
+		return ""
+	}
+	return fmt.Sprintf(", first=%v, final=%v, speedup=%.1fx",
+		r.FirstRequestLatency.Round(time.Microsecond), r.FinalWindowLatency.Round(time.Microsecond), r.SpeedupRatio)
+}
+
+// failedRequestsSuffix names the scenario requests that failed, if any, for the
+// result message.
+func (r *Result) failedRequestsSuffix() string {
+	failed := r.FailedRequests()
+	if len(failed) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, maxMessageRequests+1)
+	for _, req := range failed[:min(len(failed), maxMessageRequests)] {
+		parts = append(parts, fmt.Sprintf("%s (%s)", req.Name, req.Describe()))
+	}
+	if len(failed) > maxMessageRequests {
+		parts = append(parts, fmt.Sprintf("and %d more", len(failed)-maxMessageRequests))
+	}
+	return "; failed requests: " + strings.Join(parts, ", ")
 }
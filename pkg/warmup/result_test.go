@@ -0,0 +1,136 @@
+package warmup
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResult_BuildMessage_FailedRequests(t *testing.T) {
+	step := func(requests ...RequestResult) StepResult {
+		s := StepResult{Requests: requests}
+		for _, r := range requests {
+			s.RequestsCompleted += r.RequestsCompleted
+			s.RequestsFailed += r.RequestsFailed
+		}
+		return s
+	}
+	failed := func(name string, codes map[string]int, errs ...string) RequestResult {
+		r := RequestResult{Name: name, StatusCodes: codes, Errors: errs}
+		for code, n := range codes {
+			if code == "OK" || code < "400" {
+				r.RequestsCompleted += n
+			} else {
+				r.RequestsFailed += n
+			}
+		}
+		r.RequestsFailed += len(errs)
+		return r
+	}
+
+	tests := []struct {
+		name   string
+		result *Result
+		want   string
+	}{
+		{
+			name: "single-endpoint warmup",
+			result: &Result{Success: true, RequestsCompleted: 3, RequestsFailed: 1, TotalDuration: time.Second,
+				LatencyP50: time.Millisecond, LatencyP99: 2 * time.Millisecond},
+			want: "warmup completed: 3/4 requests succeeded (75.0%), duration=1s, P50=1ms, P99=2ms",
+		},
+		{
+			name: "single-endpoint warmup with a cold start",
+			result: &Result{Success: true, RequestsCompleted: 3, TotalDuration: time.Second,
+				LatencyP50: time.Millisecond, LatencyP99: 90 * time.Millisecond,
+				FirstRequestLatency: 90 * time.Millisecond, FinalWindowLatency: time.Millisecond, SpeedupRatio: 90},
+			want: "warmup completed: 3/3 requests succeeded (100.0%), duration=1s, P50=1ms, P99=90ms, " +
+				"first=90ms, final=1ms, speedup=90.0x",
+		},
+		{
+			name: "successful scenario with a failed request",
+			result: &Result{Success: true, RequestsCompleted: 3, RequestsFailed: 2, TotalDuration: time.Second,
+				Steps: []StepResult{step(failed("list", map[string]int{"200": 3})), step(failed("detail", map[string]int{"503": 2}))}},
+			want: "warmup completed: 3/5 requests succeeded (60.0%), duration=1s, P50=0s, P99=0s; " +
+				"failed requests: detail (2/2 failed, status 503×2)",
+		},
+		{
+			name: "failed scenario",
+			result: &Result{RequestsFailed: 5,
+				Steps: []StepResult{step(
+					failed("a", map[string]int{"200": 1, "500": 1}),
+					failed("b", nil, "connection refused"),
+					failed("c", map[string]int{"404": 1}),
+					failed("d", map[string]int{"404": 1}),
+				)}},
+			want: "warmup completed with failures: 0/5 requests succeeded (0.0%); failed requests: " +
+				"a (1/2 failed, status 200×1 500×1), b (1/1 failed, error: connection refused), " +
+				"c (1/1 failed, status 404×1), and 1 more",
+		},
+		{
+			name: "gRPC scenario",
+			result: &Result{Success: true, RequestsCompleted: 3, RequestsFailed: 2, TotalDuration: time.Second,
+				Steps: []StepResult{step(failed("get", map[string]int{"OK": 3, "UNAVAILABLE": 2}))}},
+			want: "warmup completed: 3/5 requests succeeded (60.0%), duration=1s, P50=0s, P99=0s; " +
+				"failed requests: get (2/5 failed, status OK×3 UNAVAILABLE×2)",
+		},
+		{
+			name: "scenario timeout",
+			result: &Result{Error: errors.New("context deadline exceeded"), RequestsFailed: 1,
+				Steps: []StepResult{step(failed("slow", nil, strings.Repeat("x", 200)))}},
+			want: "warmup failed: context deadline exceeded; failed requests: slow (1/1 failed, error: " +
+				strings.Repeat("x", maxMessageError-3) + "...)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.result.BuildMessage(); got != tt.want {
+				t.Errorf("BuildMessage() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResult_measureEffectiveness(t *testing.T) {
+	ms := func(values ...int) []time.Duration {
+		latencies := make([]time.Duration, len(values))
+		for i, v := range values {
+			latencies[i] = time.Duration(v) * time.Millisecond
+		}
+		return latencies
+	}
+
+	tests := []struct {
+		name      string
+		latencies []time.Duration
+		wantFirst time.Duration
+		wantFinal time.Duration
+		wantRatio float64
+	}{
+		{name: "no responses"},
+		{name: "single response", latencies: ms(50), wantFirst: 50 * time.Millisecond},
+		{name: "two responses", latencies: ms(50, 10), wantFirst: 50 * time.Millisecond, wantFinal: 10 * time.Millisecond, wantRatio: 5},
+		{
+			// The final window is the last 10% of the 20 responses after the first
+			name:      "window",
+			latencies: ms(100, 50, 40, 30, 20, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 2, 6),
+			wantFirst: 100 * time.Millisecond,
+			wantFinal: 4 * time.Millisecond,
+			wantRatio: 25,
+		},
+		{name: "slower at the end", latencies: ms(10, 20), wantFirst: 10 * time.Millisecond, wantFinal: 20 * time.Millisecond, wantRatio: 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Result{}
+			r.measureEffectiveness(tt.latencies)
+			if r.FirstRequestLatency != tt.wantFirst || r.FinalWindowLatency != tt.wantFinal || r.SpeedupRatio != tt.wantRatio {
+				t.Errorf("got first=%v final=%v ratio=%v, want first=%v final=%v ratio=%v",
+					r.FirstRequestLatency, r.FinalWindowLatency, r.SpeedupRatio, tt.wantFirst, tt.wantFinal, tt.wantRatio)
+			}
+		})
+	}
+}
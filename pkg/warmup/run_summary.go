@@ -0,0 +1,74 @@
+package warmup
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+// RunSummary is the outcome of a pod's WarmupConfig warmup. The node-local
+// controller stores it as JSON in the kube-booster.io/warmup-result annotation, and
+// the WarmupConfig status reconciler aggregates the summaries of all pods that use
+// a config.
+type RunSummary struct {
+	// Config is the warmup-config reference the run used, in annotation form
+	// (see Config.WarmupConfigRef).
+	Config string `json:"config"`
+
+	// Time is when the run finished.
+	Time metav1.Time `json:"time"`
+
+	// Success reports whether the warmup succeeded.
+	Success bool `json:"success"`
+
+	// DurationMillis is the time the scenario took, in milliseconds.
+	DurationMillis int64 `json:"durationMs"`
+
+	// FailedSteps lists the names of the steps that had failed requests, in order.
+	FailedSteps []string `json:"failedSteps,omitempty"`
+}
+
+// NewRunSummary summarizes result, a run of the WarmupConfig that config
+// references, finished at now.
+func NewRunSummary(config *Config, result *Result, now time.Time) *RunSummary {
+	s := &RunSummary{
+		Config:         config.WarmupConfigRef(),
+		Time:           metav1.NewTime(now),
+		Success:        result.Success,
+		DurationMillis: result.TotalDuration.Milliseconds(),
+	}
+	for _, step := range result.Steps {
+		if step.Failed() {
+			s.FailedSteps = append(s.FailedSteps, step.Name)
+		}
+	}
+	return s
+}
+
+// ParseRunSummary reads the result annotation from pod. It returns nil if the
+// annotation is not set.
+func ParseRunSummary(pod *corev1.Pod) (*RunSummary, error) {
+	value, ok := pod.Annotations[webhook.AnnotationWarmupResult]
+	if !ok || value == "" {
+		return nil, nil
+	}
+	s := &RunSummary{}
+	if err := json.Unmarshal([]byte(value), s); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", webhook.AnnotationWarmupResult, err)
+	}
+	return s, nil
+}
+
+// Annotation returns s encoded as the value of the result annotation.
+func (s *RunSummary) Annotation() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,57 @@
+package warmup
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestNewRunSummary(t *testing.T) {
+	config := &Config{WarmupConfigKind: webhook.KindClusterWarmupConfig, WarmupConfigName: "jvm"}
+	result := &Result{
+		Success:       true,
+		TotalDuration: 2500 * time.Millisecond,
+		Steps: []StepResult{
+			{Name: "login", RequestsCompleted: 1},
+			{Name: "browse", RequestsCompleted: 4, RequestsFailed: 1},
+			{Name: "checkout", RequestsFailed: 2},
+		},
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s := NewRunSummary(config, result, now)
+	if s.Config != "ClusterWarmupConfig/jvm" || !s.Success || s.DurationMillis != 2500 || !s.Time.Time.Equal(now) {
+		t.Errorf("NewRunSummary() = %+v", s)
+	}
+	if want := []string{"browse", "checkout"}; !slices.Equal(s.FailedSteps, want) {
+		t.Errorf("FailedSteps = %v, want %v", s.FailedSteps, want)
+	}
+}
+
+func TestParseRunSummary(t *testing.T) {
+	want := &RunSummary{Config: "catalog", Time: metav1.NewTime(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)), DurationMillis: 40, FailedSteps: []string{"login"}}
+	value, err := want.Annotation()
+	if err != nil {
+		t.Fatalf("Annotation() error = %v", err)
+	}
+
+	got, err := ParseRunSummary(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{webhook.AnnotationWarmupResult: value}}})
+	if err != nil {
+		t.Fatalf("ParseRunSummary() error = %v", err)
+	}
+	if got.Config != want.Config || !got.Time.Equal(&want.Time) || got.DurationMillis != want.DurationMillis || !slices.Equal(got.FailedSteps, want.FailedSteps) {
+		t.Errorf("ParseRunSummary() = %+v, want %+v", got, want)
+	}
+
+	if got, err := ParseRunSummary(&corev1.Pod{}); got != nil || err != nil {
+		t.Errorf("ParseRunSummary() without annotation = (%v, %v), want (nil, nil)", got, err)
+	}
+	if _, err := ParseRunSummary(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{webhook.AnnotationWarmupResult: "ok"}}}); err == nil {
+		t.Error("ParseRunSummary() expected an error for invalid JSON")
+	}
+}
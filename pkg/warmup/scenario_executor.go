@@ -4,13 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand/v2"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
+	"google.golang.org/grpc/codes"
 
 	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 const (
@@ -38,13 +43,23 @@ func WithScenarioRateLimiter(rl *RequestRateLimiter) ScenarioExecutorOption {
 	}
 }
 
+// WithScenarioSigner signs every scenario request with the given signer.
+// Passing nil disables signing.
+func WithScenarioSigner(s *signing.Signer) ScenarioExecutorOption {
+	return func(e *defaultScenarioExecutor) {
+		e.signer = s
+	}
+}
+
 // defaultScenarioExecutor orchestrates multi-step, scenario-based warmup defined in a
 // WarmupConfig CRD. Steps are executed sequentially; within a step, requests are
-// executed sequentially with optional {{varName}} interpolation from prior responses.
+// executed sequentially (or sampled by weight for mix steps) with optional
+// {{varName}} interpolation from prior responses.
 type defaultScenarioExecutor struct {
 	logger      logr.Logger
 	rateLimiter *RequestRateLimiter
 	httpClient  *http.Client
+	signer      *signing.Signer
 }
 
 // NewScenarioExecutor creates a new ScenarioExecutor.
@@ -92,39 +107,90 @@ func (e *defaultScenarioExecutor) ExecuteScenario(
 		return &Result{Success: true, Message: "scenario warmup skipped: no steps defined"}
 	}
 
+	// The per-execution limiter composes with the shared one; the lower of the pod
+	// annotation and the WarmupConfig rate limit applies.
+	limiter := NewRequestRateLimiter(minRPS(config.RPS, float64(spec.RateLimit)))
+
 	session := NewSessionContext()
+	for name, value := range config.Params {
+		session.Set(ParamVariablePrefix+name, value)
+	}
+	first := resumePoint(spec, config.Progress, session)
+	completedSteps := make([]string, 0, len(spec.Steps))
+	for stepIdx := range first {
+		completedSteps = append(completedSteps, StepName(spec.Steps[stepIdx], stepIdx))
+	}
+	if first > 0 {
+		e.logger.Info("resuming scenario after an interrupted attempt",
+			"pod", config.PodName,
+			"namespace", config.PodNamespace,
+			"skippedSteps", completedSteps)
+	}
+
 	start := time.Now()
 	totalCompleted, totalFailed := 0, 0
+	// Latencies of all responses, in the order the requests were sent
+	var timeline []time.Duration
+	// Progress is a prefix of the steps; it stops at the first step to redo
+	recording := true
 
-	for stepIdx, step := range spec.Steps {
+	for stepIdx := first; stepIdx < len(spec.Steps); stepIdx++ {
 		if scenarioCtx.Err() != nil {
 			break
 		}
 
-		stepName := step.Name
-		if stepName == "" {
-			stepName = fmt.Sprintf("step-%d", stepIdx+1)
-		}
+		step := spec.Steps[stepIdx]
+		stepName := StepName(step, stepIdx)
 
-		stepTimeout := defaultStepTimeout
-		if step.Timeout != "" {
-			if d, err := time.ParseDuration(step.Timeout); err == nil && d > 0 {
-				stepTimeout = d
+		stepStart := time.Now()
+		stepCtx, stepCancel := context.WithTimeout(scenarioCtx, stepTimeout(step))
+		requests, unsent := e.executeStep(stepCtx, config, step, session, stepName, limiter, &timeline)
+		stepCancel()
+		stepResult := StepResult{Name: stepName, RequestsFailed: unsent, Duration: time.Since(stepStart)}
+		var stepLatencies []time.Duration
+		for _, req := range requests {
+			stepLatencies = append(stepLatencies, req.latencies...)
+			stepResult.Requests = append(stepResult.Requests, req.finish())
+			stepResult.RequestsCompleted += req.RequestsCompleted
+			stepResult.RequestsFailed += req.RequestsFailed
+			for class, n := range req.Outcomes {
+				result.countOutcomes(class, n)
 			}
 		}
-
-		stepCtx, stepCancel := context.WithTimeout(scenarioCtx, stepTimeout)
-		completed, failed := e.executeStep(stepCtx, config, step, session, stepName)
-		stepCancel()
+		stepResult.LatencyP50, stepResult.LatencyP99 = calculatePercentiles(stepLatencies)
+		result.Steps = append(result.Steps, stepResult)
+		completed, failed := stepResult.RequestsCompleted, stepResult.RequestsFailed
 
 		totalCompleted += completed
 		totalFailed += failed
+		// A step cut short by the end of the scenario, or whose requests all
+		// failed, has to run again on resume
+		recording = recording && scenarioCtx.Err() == nil && stepResult.succeeded()
+		if recording {
+			completedSteps = append(completedSteps, stepName)
+			// The result reports the progress of the last step
+			if config.OnProgress != nil && stepIdx < len(spec.Steps)-1 {
+				config.OnProgress(&Progress{
+					Steps:     slices.Clone(completedSteps),
+					Variables: session.Values(spec.PersistVariables),
+				})
+			}
+		}
 	}
 
 	result.RequestsCompleted = totalCompleted
 	result.RequestsFailed = totalFailed
 	result.TotalDuration = time.Since(start)
-	result.Success = totalCompleted > 0
+	// Measured before the percentiles, which sort the timeline
+	result.measureEffectiveness(timeline)
+	result.LatencyP50, result.LatencyP99 = calculatePercentiles(timeline)
+	// A resumed scenario whose remaining steps were all done by an earlier attempt
+	// has nothing left to send.
+	result.Success = totalCompleted > 0 || first == len(spec.Steps)
+	result.Progress = &Progress{
+		Steps:     completedSteps,
+		Variables: session.Values(spec.PersistVariables),
+	}
 
 	if scenarioCtx.Err() != nil {
 		result.Error = scenarioCtx.Err()
@@ -137,134 +203,457 @@ func (e *defaultScenarioExecutor) ExecuteScenario(
 		"namespace", config.PodNamespace,
 		"requestsCompleted", totalCompleted,
 		"requestsFailed", totalFailed,
+		"latencyP50", result.LatencyP50,
+		"latencyP99", result.LatencyP99,
+		"firstRequestLatency", result.FirstRequestLatency,
+		"speedupRatio", result.SpeedupRatio,
+		"outcomes", result.Outcomes,
 		"duration", result.TotalDuration)
 
 	return result
 }
 
-// executeStep runs all requests in a step sequentially and returns (completed, failed).
+// resumePoint returns the index of the first step to run, given the progress of an
+// earlier attempt, and restores the persisted session variables. Completed steps are
+// skipped only while their names match the spec in order, and never past a step
+// that extracted a variable that was not persisted, since it must be extracted again.
+func resumePoint(spec *v1alpha1.WarmupConfigSpec, progress *Progress, session *SessionContext) int {
+	if progress == nil {
+		return 0
+	}
+
+	first := 0
+	for first < len(spec.Steps) && first < len(progress.Steps) &&
+		StepName(spec.Steps[first], first) == progress.Steps[first] {
+		first++
+	}
+	for i := range first {
+		if !slices.ContainsFunc(stepExtracts(spec.Steps[i]), func(name string) bool {
+			_, ok := progress.Variables[name]
+			return !ok || !slices.Contains(spec.PersistVariables, name)
+		}) {
+			continue
+		}
+		first = i
+		break
+	}
+
+	for _, name := range spec.PersistVariables {
+		if v, ok := progress.Variables[name]; ok {
+			session.Set(name, v)
+		}
+	}
+	return first
+}
+
+// StepName returns the step's Name, or "step-<n>" if it has none.
+func StepName(step v1alpha1.WarmupStep, idx int) string {
+	if step.Name != "" {
+		return step.Name
+	}
+	return fmt.Sprintf("step-%d", idx+1)
+}
+
+// stepExtracts returns the names of the session variables the step extracts.
+func stepExtracts(step v1alpha1.WarmupStep) []string {
+	requests := step.Requests
+	if step.Mix != nil {
+		requests = step.Mix.Requests
+	}
+	var names []string
+	for _, req := range requests {
+		for name := range req.Extract {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// requestStats accumulates the responses to a request of a step.
+type requestStats struct {
+	RequestResult
+	latencies []time.Duration
+	// timeline collects the latencies of all the scenario's responses in order
+	timeline *[]time.Duration
+}
+
+// record counts resp, which counts as completed if ok.
+func (s *requestStats) record(resp *Response, ok bool) {
+	if ok {
+		s.RequestsCompleted++
+	} else {
+		s.RequestsFailed++
+	}
+	if s.Outcomes == nil {
+		s.Outcomes = make(map[string]int)
+	}
+	s.Outcomes[ClassifyResponse(resp, ok)]++
+	if resp.Error != nil {
+		if msg := resp.Error.Error(); len(s.Errors) < maxRequestErrors && !slices.Contains(s.Errors, msg) {
+			s.Errors = append(s.Errors, msg)
+		}
+		return
+	}
+	if len(s.latencies) == 0 {
+		s.FirstLatency = resp.Duration
+	}
+	s.latencies = append(s.latencies, resp.Duration)
+	*s.timeline = append(*s.timeline, resp.Duration)
+	if s.StatusCodes == nil {
+		s.StatusCodes = make(map[string]int)
+	}
+	s.StatusCodes[resp.StatusName()]++
+}
+
+// finish computes the latency percentiles and returns the result.
+func (s *requestStats) finish() RequestResult {
+	s.LatencyP50, s.LatencyP99 = calculatePercentiles(s.latencies)
+	return s.RequestResult
+}
+
+// executeStep runs a step and returns the outcome of each of its requests, and the
+// number of mix requests that were never sent because the scenario ended first
+// (counted as failed). Steps with a Mix sample requests by weight; all other steps
+// run their requests sequentially.
 func (e *defaultScenarioExecutor) executeStep(
 	ctx context.Context,
 	config *Config,
 	step v1alpha1.WarmupStep,
 	session *SessionContext,
 	stepName string,
-) (completed, failed int) {
-	// GRPCSender is created per-step (different steps may target different gRPC methods).
-	var grpcSender *GRPCSender
-	defer func() {
-		if grpcSender != nil {
-			_ = grpcSender.Close() //nolint:errcheck // gRPC connection close errors are non-actionable
-		}
-	}()
+	limiter *RequestRateLimiter,
+	timeline *[]time.Duration,
+) (requests []*requestStats, unsent int) {
+	senders := e.newRequestSenders()
+	defer senders.close()
+
+	if step.Mix != nil {
+		return e.executeMix(ctx, config, step.Mix, session, stepName, senders, limiter, timeline)
+	}
 
+	results := make([]*requestStats, 0, len(step.Requests))
 	for reqIdx, req := range step.Requests {
 		if ctx.Err() != nil {
 			break
 		}
 
-		reqName := req.Name
-		if reqName == "" {
-			reqName = fmt.Sprintf("%s/req-%d", stepName, reqIdx+1)
-		}
-
-		protocol := req.Protocol
-		if protocol == "" {
-			protocol = config.Protocol
-		}
-		if protocol == "" {
-			protocol = ProtocolHTTP
-		}
+		reqName := requestName(req, stepName, reqIdx)
+		result := &requestStats{RequestResult: RequestResult{Name: reqName}, timeline: timeline}
+		results = append(results, result)
 
 		count := req.Count
 		if count < 1 {
 			count = 1
 		}
 
+		// Requests with load stages repeat until the final stage ends; Count is ignored.
+		stages, err := requestStages(req.Stages)
+		if err != nil {
+			e.logger.V(1).Info("ignoring invalid load stages", "request", reqName, "error", err)
+		}
+		stageLimiter := NewStageRateLimiter(stages)
+
 		var lastBody []byte
-		httpSender := &HTTPSender{client: e.httpClient, logger: e.logger}
-		for i := 0; i < count; i++ {
+		for i := 0; stageLimiter != nil || i < count; i++ {
 			if ctx.Err() != nil {
 				break
 			}
-			if err := e.rateLimiter.Wait(ctx); err != nil {
-				failed += count - i
+			if err := stageLimiter.Wait(ctx); err != nil {
 				break
 			}
-
-			var resp *Response
-			switch protocol {
-			case ProtocolGRPC:
-				if grpcSender == nil {
-					grpcSender = NewGRPCSender(e.logger)
-				}
-				resp = grpcSender.Send(ctx, Target{
-					Address: config.BuildGRPCAddress(),
-					Method:  req.GRPCMethod,
-					Payload: []byte(session.Interpolate(req.GRPCPayload)),
-				})
-			default:
-				endpoint := session.Interpolate(req.Endpoint)
-				if endpoint == "" {
-					endpoint = DefaultEndpointPath
+			if err := waitAll(ctx, limiter, e.rateLimiter); err != nil {
+				if stageLimiter == nil {
+					result.RequestsFailed += count - i
 				}
-				body := []byte(session.Interpolate(req.Body))
+				break
+			}
 
-				interpolatedHeaders := make(map[string]string, len(req.Headers)+2)
-				interpolatedHeaders["User-Agent"] = "kube-booster/1.0"
-				interpolatedHeaders["X-Warmup-Request"] = "true"
-				for k, v := range req.Headers {
-					interpolatedHeaders[k] = session.Interpolate(v)
-				}
+			resp, ok := e.sendRequest(ctx, config, req, session, senders, reqName)
+			if resp.Error == nil {
+				lastBody = resp.Body
+			}
+			result.record(resp, ok)
+		}
 
-				method := req.Method
-				if method == "" {
-					method = http.MethodGet
-				}
+		// Extract session variables from the last response body.
+		if len(req.Extract) > 0 && len(lastBody) > 0 {
+			extractVariables(lastBody, req.Extract, session, e.logger, reqName)
+		}
+	}
+	return results, 0
+}
 
-				resp = httpSender.Send(ctx, Target{
-					Address: config.BuildEndpointURLFor(endpoint),
-					Method:  method,
-					Headers: interpolatedHeaders,
-					Payload: body,
-				})
-			}
+// defaultMixRequests is the request budget for a mix with neither TotalRequests nor
+// a valid Duration set.
+const defaultMixRequests = 100
+
+// stepTimeoutGrace is added to the duration of a mix or of load stages to get the
+// timeout of a step without one, so that the requests in flight when the duration
+// ends can finish.
+const stepTimeoutGrace = 10 * time.Second
+
+// stepTimeout returns the time limit of step. A step without a timeout of its own
+// gets at least its mix duration, or the total duration of its requests' load
+// stages, plus stepTimeoutGrace.
+func stepTimeout(step v1alpha1.WarmupStep) time.Duration {
+	if step.Timeout != "" {
+		if d, err := time.ParseDuration(step.Timeout); err == nil && d > 0 {
+			return d
+		}
+	}
+	if step.Mix != nil {
+		if d, ok := mixDuration(step.Mix); ok {
+			return max(defaultStepTimeout, d+stepTimeoutGrace)
+		}
+		return defaultStepTimeout
+	}
+	if d := stagesDuration(step.Requests); d > 0 {
+		return max(defaultStepTimeout, d+stepTimeoutGrace)
+	}
+	return defaultStepTimeout
+}
 
-			if resp.Error != nil {
-				failed++
-				e.logger.V(2).Info("request failed", "request", reqName, "error", resp.Error)
-				continue
-			}
+// stagesDuration returns how long the load stages of requests run in total. The
+// requests of a step run one after another, so their stages add up. Invalid stage
+// lists, which are ignored when the step runs, count as zero.
+func stagesDuration(requests []v1alpha1.WarmupRequest) time.Duration {
+	var total time.Duration
+	for _, req := range requests {
+		stages, err := requestStages(req.Stages)
+		if err != nil {
+			continue
+		}
+		for _, stage := range stages {
+			total += stage.Duration
+		}
+	}
+	return total
+}
 
-			lastBody = resp.Body
+// mixDuration returns the duration of mix, if it has a valid one.
+func mixDuration(mix *v1alpha1.WarmupMix) (time.Duration, bool) {
+	if mix.Duration == "" {
+		return 0, false
+	}
+	d, err := time.ParseDuration(mix.Duration)
+	return d, err == nil && d > 0
+}
 
-			ok := isSuccess(resp.StatusCode, req.ExpectedStatus, protocol)
-			if ok {
-				completed++
-			} else {
-				failed++
-				e.logger.V(2).Info("request returned unexpected status",
-					"request", reqName, "status", resp.StatusCode, "expected", req.ExpectedStatus)
+// executeMix samples requests from mix in proportion to their weights until the
+// request budget or duration is exhausted. Extraction rules are applied after every
+// response so that later samples can use the most recent values.
+func (e *defaultScenarioExecutor) executeMix(
+	ctx context.Context,
+	config *Config,
+	mix *v1alpha1.WarmupMix,
+	session *SessionContext,
+	stepName string,
+	senders *requestSenders,
+	limiter *RequestRateLimiter,
+	timeline *[]time.Duration,
+) (results []*requestStats, unsent int) {
+	if len(mix.Requests) == 0 {
+		return nil, 0
+	}
+	results = make([]*requestStats, len(mix.Requests))
+	for i, req := range mix.Requests {
+		results[i] = &requestStats{RequestResult: RequestResult{Name: requestName(req, stepName, i)}, timeline: timeline}
+	}
+
+	budget := mix.TotalRequests
+	mixCtx := ctx
+	d, timed := mixDuration(mix)
+	if timed {
+		var cancel context.CancelFunc
+		mixCtx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	} else if mix.Duration != "" {
+		e.logger.V(1).Info("ignoring invalid mix duration", "step", stepName, "duration", mix.Duration)
+	}
+	if budget < 1 && !timed {
+		budget = defaultMixRequests
+	}
+
+	cumulative := make([]int, len(mix.Requests))
+	totalWeight := 0
+	for i, req := range mix.Requests {
+		totalWeight += max(1, req.Weight)
+		cumulative[i] = totalWeight
+	}
+
+	for sent := 0; budget < 1 || sent < budget; sent++ {
+		if mixCtx.Err() != nil {
+			break
+		}
+		if err := waitAll(mixCtx, limiter, e.rateLimiter); err != nil {
+			if budget > 0 && ctx.Err() != nil {
+				unsent = budget - sent
 			}
+			break
 		}
 
-		// Extract session variables from the last response body.
-		if len(req.Extract) > 0 && len(lastBody) > 0 {
-			extractVariables(lastBody, req.Extract, session, e.logger, reqName)
+		idx, _ := slices.BinarySearch(cumulative, rand.IntN(totalWeight)+1)
+		req := mix.Requests[idx]
+		reqName := results[idx].Name
+
+		resp, ok := e.sendRequest(mixCtx, config, req, session, senders, reqName)
+		// A request cut off by the end of the mix duration is not counted as failed
+		if cutOff := resp.Error != nil && mixCtx.Err() != nil && ctx.Err() == nil; !cutOff {
+			results[idx].record(resp, ok)
+		}
+		if resp.Error == nil && len(req.Extract) > 0 && len(resp.Body) > 0 {
+			extractVariables(resp.Body, req.Extract, session, e.logger, reqName)
 		}
 	}
-	return completed, failed
+	return results, unsent
+}
+
+// requestStages converts CRD load stages into Stages.
+func requestStages(in []v1alpha1.LoadStage) ([]Stage, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	stages := make([]Stage, 0, len(in))
+	for i, st := range in {
+		stage, err := parseStage(strconv.Itoa(st.RPS), st.Duration)
+		if err != nil {
+			return nil, fmt.Errorf("stage %d: %w", i+1, err)
+		}
+		stages = append(stages, stage)
+	}
+	return stages, nil
+}
+
+// requestName returns the request's Name, or "<step>/req-<n>" if it has none.
+func requestName(req v1alpha1.WarmupRequest, stepName string, idx int) string {
+	if req.Name != "" {
+		return req.Name
+	}
+	return fmt.Sprintf("%s/req-%d", stepName, idx+1)
+}
+
+// requestSenders lazily creates the senders used within a single step.
+type requestSenders struct {
+	http *HTTPSender
+	// grpc is keyed by gRPC method because a GRPCSender caches the descriptor of the
+	// first method it calls.
+	grpc   map[string]*GRPCSender
+	logger logr.Logger
+	signer *signing.Signer
+}
+
+func (e *defaultScenarioExecutor) newRequestSenders() *requestSenders {
+	return &requestSenders{
+		http:   &HTTPSender{client: e.httpClient, logger: e.logger, signer: e.signer},
+		grpc:   make(map[string]*GRPCSender),
+		logger: e.logger,
+		signer: e.signer,
+	}
+}
+
+// grpcFor returns the GRPCSender for method, creating it on first use.
+func (s *requestSenders) grpcFor(method string) *GRPCSender {
+	sender, ok := s.grpc[method]
+	if !ok {
+		sender = NewGRPCSender(s.logger)
+		sender.signer = s.signer
+		s.grpc[method] = sender
+	}
+	return sender
+}
+
+func (s *requestSenders) close() {
+	for _, sender := range s.grpc {
+		_ = sender.Close() //nolint:errcheck // gRPC connection close errors are non-actionable
+	}
+}
+
+// sendRequest sends one interpolated request and reports whether it counts as completed.
+func (e *defaultScenarioExecutor) sendRequest(
+	ctx context.Context,
+	config *Config,
+	req v1alpha1.WarmupRequest,
+	session *SessionContext,
+	senders *requestSenders,
+	reqName string,
+) (*Response, bool) {
+	protocol := req.Protocol
+	if protocol == "" {
+		protocol = config.Protocol
+	}
+	if protocol == "" {
+		protocol = ProtocolHTTP
+	}
+
+	var resp *Response
+	switch protocol {
+	case ProtocolGRPC:
+		resp = senders.grpcFor(req.GRPCMethod).Send(ctx, Target{
+			Address: config.BuildGRPCAddress(),
+			Method:  req.GRPCMethod,
+			Payload: []byte(session.Interpolate(req.GRPCPayload)),
+		})
+	default:
+		endpoint := session.Interpolate(req.Endpoint)
+		if endpoint == "" {
+			endpoint = DefaultEndpointPath
+		}
+		body := []byte(session.Interpolate(req.Body))
+
+		interpolatedHeaders := make(map[string]string, len(req.Headers)+2)
+		interpolatedHeaders["User-Agent"] = "kube-booster/1.0"
+		interpolatedHeaders["X-Warmup-Request"] = "true"
+		for k, v := range req.Headers {
+			interpolatedHeaders[k] = session.Interpolate(v)
+		}
+
+		method := req.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+
+		resp = senders.http.Send(ctx, Target{
+			Address: config.BuildEndpointURLFor(endpoint),
+			Method:  method,
+			Headers: interpolatedHeaders,
+			Payload: body,
+		})
+	}
+
+	if resp.Error != nil {
+		e.logger.V(2).Info("request failed", "request", reqName, "error", resp.Error)
+		return resp, false
+	}
+
+	ok := isSuccess(resp, req, protocol)
+	if !ok {
+		var expected any = req.ExpectedStatus
+		if protocol == ProtocolGRPC {
+			expected = expectedGRPCCode(req)
+		}
+		e.logger.V(2).Info("request returned unexpected status",
+			"request", reqName, "status", resp.StatusName(), "expected", expected)
+	}
+	return resp, ok
 }
 
 // isSuccess returns true when the response should be counted as completed.
-func isSuccess(statusCode, expectedStatus int, protocol string) bool {
+func isSuccess(resp *Response, req v1alpha1.WarmupRequest, protocol string) bool {
 	if protocol == ProtocolGRPC {
-		return statusCode == 200
+		return resp.Status != nil && resp.Status.Code == expectedGRPCCode(req)
+	}
+	if req.ExpectedStatus != 0 {
+		return resp.StatusCode == req.ExpectedStatus
 	}
-	if expectedStatus != 0 {
-		return statusCode == expectedStatus
+	return resp.StatusCode >= 200 && resp.StatusCode < 400
+}
+
+// expectedGRPCCode returns the gRPC code that counts as success for req.
+func expectedGRPCCode(req v1alpha1.WarmupRequest) string {
+	if req.ExpectedGRPCCode != "" {
+		return req.ExpectedGRPCCode
 	}
-	return statusCode >= 200 && statusCode < 400
+	return grpcCodeNames[codes.OK]
 }
 
 // extractVariables parses body as JSON and stores matched JSONPath values in session.
@@ -289,10 +678,10 @@ func extractVariables(body []byte, extract map[string]string, session *SessionCo
 // jsonPathLookup resolves a simple dot-path expression ($.key or $.a.b) in a JSON map.
 // Only map traversal is supported; arrays and filter expressions are not.
 func jsonPathLookup(doc map[string]any, path string) (any, error) {
-	if !strings.HasPrefix(path, "$.") {
-		return nil, fmt.Errorf("unsupported JSONPath expression %q: must start with '$.'", path)
+	parts, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
 	}
-	parts := strings.Split(strings.TrimPrefix(path, "$."), ".")
 	var cur any = doc
 	for _, part := range parts {
 		m, ok := cur.(map[string]any)
@@ -306,3 +695,20 @@ func jsonPathLookup(doc map[string]any, path string) (any, error) {
 	}
 	return cur, nil
 }
+
+// parseJSONPath splits a simple dot-path expression ($.key or $.a.b) into its keys.
+func parseJSONPath(path string) ([]string, error) {
+	if !strings.HasPrefix(path, "$.") {
+		return nil, fmt.Errorf("unsupported JSONPath expression %q: must start with '$.'", path)
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "$."), ".")
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("unsupported JSONPath expression %q: empty key", path)
+		}
+		if strings.ContainsAny(part, "[]*?@()") {
+			return nil, fmt.Errorf("unsupported JSONPath expression %q: only $.key and $.a.b are supported (no arrays, wildcards or filters)", path)
+		}
+	}
+	return parts, nil
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
 	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 func newTestConfig(podIP string, port int) *Config {
@@ -192,6 +197,331 @@ func TestScenarioExecutor_ResponseChaining(t *testing.T) {
 	}
 }
 
+func TestScenarioExecutor_Resume(t *testing.T) {
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Name: "login", Requests: []v1alpha1.WarmupRequest{{
+				Endpoint: "/login",
+				Extract:  map[string]string{"token": "$.token"},
+			}}},
+			{Name: "load-cache", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/cache"}}},
+			{Requests: []v1alpha1.WarmupRequest{{
+				Endpoint: "/verify",
+				Headers:  map[string]string{"Authorization": "Bearer {{token}}"},
+			}}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		persist   []string
+		progress  *Progress
+		wantPaths []string
+		wantAuth  string
+		wantVars  map[string]any
+	}{
+		{
+			name:      "fresh start",
+			persist:   []string{"token"},
+			wantPaths: []string{"/login", "/cache", "/verify"},
+			wantAuth:  "Bearer fresh",
+			wantVars:  map[string]any{"token": "fresh"},
+		},
+		{
+			name:      "resumes after completed steps",
+			persist:   []string{"token"},
+			progress:  &Progress{Attempt: 1, Steps: []string{"login", "load-cache"}, Variables: map[string]any{"token": "saved"}},
+			wantPaths: []string{"/verify"},
+			wantAuth:  "Bearer saved",
+			wantVars:  map[string]any{"token": "saved"},
+		},
+		{
+			name:      "reruns step whose variable was not persisted",
+			progress:  &Progress{Attempt: 1, Steps: []string{"login", "load-cache"}},
+			wantPaths: []string{"/login", "/cache", "/verify"},
+			wantAuth:  "Bearer fresh",
+		},
+		{
+			name:      "ignores variables no longer allowed to persist",
+			progress:  &Progress{Attempt: 1, Steps: []string{"login"}, Variables: map[string]any{"token": "saved"}},
+			wantPaths: []string{"/login", "/cache", "/verify"},
+			wantAuth:  "Bearer fresh",
+		},
+		{
+			name:      "restarts when steps no longer match",
+			persist:   []string{"token"},
+			progress:  &Progress{Attempt: 1, Steps: []string{"setup"}, Variables: map[string]any{"token": "saved"}},
+			wantPaths: []string{"/login", "/cache", "/verify"},
+			wantAuth:  "Bearer fresh",
+			wantVars:  map[string]any{"token": "fresh"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				paths []string
+				auth  string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				paths = append(paths, r.URL.Path)
+				if r.URL.Path == "/verify" {
+					auth = r.Header.Get("Authorization")
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"token":"fresh"}`)) //nolint:errcheck // test handler
+			}))
+			defer server.Close()
+
+			e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+			host, port := parseTestServerAddr(t, server.URL)
+			config := newTestConfig(host, port)
+			config.Progress = tt.progress
+			spec := *spec
+			spec.PersistVariables = tt.persist
+
+			result := e.ExecuteScenario(context.Background(), config, &spec)
+			if !result.Success {
+				t.Errorf("expected success, got: %s", result.Message)
+			}
+			if fmt.Sprint(paths) != fmt.Sprint(tt.wantPaths) {
+				t.Errorf("requested paths = %v, want %v", paths, tt.wantPaths)
+			}
+			if auth != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", auth, tt.wantAuth)
+			}
+			if result.Progress == nil {
+				t.Fatal("result.Progress is nil")
+			}
+			if got := fmt.Sprint(result.Progress.Steps); got != "[login load-cache step-3]" {
+				t.Errorf("completed steps = %s, want [login load-cache step-3]", got)
+			}
+			if fmt.Sprint(result.Progress.Variables) != fmt.Sprint(tt.wantVars) {
+				t.Errorf("persisted variables = %v, want %v", result.Progress.Variables, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestScenarioExecutor_InterruptedStepNotCompleted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			cancel()
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Name: "fast", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/fast"}}},
+			{Name: "slow", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/slow"}}},
+		},
+	}
+
+	result := e.ExecuteScenario(ctx, newTestConfig(host, port), spec)
+	if result.Progress == nil || fmt.Sprint(result.Progress.Steps) != "[fast]" {
+		t.Errorf("result.Progress = %+v, want only step fast completed", result.Progress)
+	}
+}
+
+func TestScenarioExecutor_OnProgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"abc"}`)) //nolint:errcheck // test server
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	spec := &v1alpha1.WarmupConfigSpec{
+		PersistVariables: []string{"token"},
+		Steps: []v1alpha1.WarmupStep{
+			{Name: "login", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/login", Extract: map[string]string{"token": "$.token"}}}},
+			{Name: "browse", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/browse"}}},
+			{Name: "broken", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/broken"}}},
+			{Name: "checkout", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/checkout"}}},
+		},
+	}
+
+	var got []string
+	config := newTestConfig(host, port)
+	config.OnProgress = func(p *Progress) {
+		got = append(got, fmt.Sprint(p.Steps, p.Variables))
+	}
+	e.ExecuteScenario(context.Background(), config, spec)
+
+	// Nothing is recorded once a step fails
+	want := []string{"[login] map[token:abc]", "[login browse] map[token:abc]"}
+	if !slices.Equal(got, want) {
+		t.Errorf("OnProgress calls = %q, want %q", got, want)
+	}
+}
+
+func TestScenarioExecutor_FailedStepNotCompleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Name: "login", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/login"}}},
+			{Name: "broken", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/broken"}}},
+			{Name: "browse", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/browse"}}},
+		},
+	}
+
+	result := e.ExecuteScenario(context.Background(), newTestConfig(host, port), spec)
+	if len(result.Steps) != 3 {
+		t.Fatalf("ran %d steps, want 3", len(result.Steps))
+	}
+	// The failed step and everything after it run again on resume
+	if result.Progress == nil || fmt.Sprint(result.Progress.Steps) != "[login]" {
+		t.Errorf("result.Progress = %+v, want only step login completed", result.Progress)
+	}
+}
+
+func TestScenarioExecutor_Stages(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{
+				Requests: []v1alpha1.WarmupRequest{
+					{
+						Endpoint: "/",
+						Count:    1, // ignored when stages are set
+						Stages: []v1alpha1.LoadStage{
+							{RPS: 20, Duration: "200ms"},
+							{RPS: 50, Duration: "200ms"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	start := time.Now()
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if !result.Success {
+		t.Fatalf("expected success, got: %s", result.Message)
+	}
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Errorf("scenario finished after %v, want >= 400ms", elapsed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if calls < 8 || calls > 20 {
+		t.Errorf("expected about 14 HTTP calls, got %d", calls)
+	}
+	if result.RequestsCompleted != calls {
+		t.Errorf("RequestsCompleted = %d, want %d", result.RequestsCompleted, calls)
+	}
+}
+
+func TestScenarioExecutor_RateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+	config.RPS = 2 // lower than spec.RateLimit, so it wins
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		RateLimit: 50,
+		Steps: []v1alpha1.WarmupStep{
+			{
+				Requests: []v1alpha1.WarmupRequest{
+					{Endpoint: "/", Count: 4},
+				},
+			},
+		},
+	}
+
+	start := time.Now()
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if result.RequestsCompleted != 4 {
+		t.Errorf("expected 4 completed, got %d", result.RequestsCompleted)
+	}
+	// Burst of 2, then two more requests at 2 rps.
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("scenario finished after %v, want >= ~1s", elapsed)
+	}
+}
+
+func TestScenarioExecutor_Params(t *testing.T) {
+	var gotPath, gotTenant string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotTenant = r.Header.Get("X-Tenant")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+	config.Params = map[string]string{"tenant": "acme", "region": "eu"}
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{
+				Requests: []v1alpha1.WarmupRequest{
+					{
+						Endpoint: "/{{params.region}}/catalog",
+						Headers:  map[string]string{"X-Tenant": "{{params.tenant}}"},
+					},
+				},
+			},
+		},
+	}
+
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if !result.Success {
+		t.Errorf("expected success, got: %s", result.Message)
+	}
+	if gotPath != "/eu/catalog" {
+		t.Errorf("path = %q, want /eu/catalog", gotPath)
+	}
+	if gotTenant != "acme" {
+		t.Errorf("X-Tenant = %q, want acme", gotTenant)
+	}
+}
+
 func TestScenarioExecutor_PostWithBody(t *testing.T) {
 	var gotMethod, gotBody string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -236,6 +566,42 @@ func TestScenarioExecutor_PostWithBody(t *testing.T) {
 	}
 }
 
+func TestScenarioExecutor_SignedRequests(t *testing.T) {
+	key := []byte("scenario-key")
+	verifier := signing.NewVerifier(key, 0)
+
+	var verified, total int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		total++
+		if verifier.VerifyRequest(r) == nil {
+			verified++
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"), WithScenarioSigner(signing.NewSigner(key)))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Requests: []v1alpha1.WarmupRequest{
+				{Endpoint: "/a"},
+				{Endpoint: "/b", Method: "POST", Body: `{"k":"v"}`, Count: 2},
+			}},
+		},
+	}
+
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if !result.Success {
+		t.Fatalf("expected success, got: %s", result.Message)
+	}
+	if total != 3 || verified != 3 {
+		t.Errorf("verified %d/%d requests, want 3/3", verified, total)
+	}
+}
+
 func TestScenarioExecutor_ExpectedStatus(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusCreated) // 201
@@ -275,6 +641,145 @@ func TestScenarioExecutor_ExpectedStatus(t *testing.T) {
 	}
 }
 
+func TestScenarioExecutor_ExpectedGRPCCode(t *testing.T) {
+	addr, stop := startTestGRPCServer(t, true)
+	defer stop()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port, _ := strings.Cut(addr, ":")
+	config := newTestConfig(host, parsePort(port))
+
+	// The health server answers NOT_FOUND for unknown services
+	check := func(service, expected string) v1alpha1.WarmupRequest {
+		return v1alpha1.WarmupRequest{
+			Name:             service,
+			Protocol:         ProtocolGRPC,
+			GRPCMethod:       "grpc.health.v1.Health/Check",
+			GRPCPayload:      fmt.Sprintf(`{"service":%q}`, service),
+			ExpectedGRPCCode: expected,
+		}
+	}
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{{Requests: []v1alpha1.WarmupRequest{
+			check("", ""),
+			check("unknown", "NOT_FOUND"),
+			check("missing", ""),
+		}}},
+	}
+	result := e.ExecuteScenario(context.Background(), config, spec)
+
+	if result.RequestsCompleted != 2 || result.RequestsFailed != 1 {
+		t.Fatalf("completed/failed = %d/%d, want 2/1: %s", result.RequestsCompleted, result.RequestsFailed, result.Message)
+	}
+	requests := result.Steps[0].Requests
+	for i, want := range []map[string]int{{"OK": 1}, {"NOT_FOUND": 1}, {"NOT_FOUND": 1}} {
+		if !maps.Equal(requests[i].StatusCodes, want) {
+			t.Errorf("request %s status codes = %v, want %v", requests[i].Name, requests[i].StatusCodes, want)
+		}
+	}
+	if !strings.Contains(result.Message, "missing (1/1 failed, status NOT_FOUND×1)") {
+		t.Errorf("Message = %q, want the failed request's gRPC code", result.Message)
+	}
+}
+
+func TestScenarioExecutor_StepResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Name: "login", Requests: []v1alpha1.WarmupRequest{{Endpoint: "/"}}},
+			{Requests: []v1alpha1.WarmupRequest{{Endpoint: "/"}, {Endpoint: "/broken"}}},
+		},
+	}
+	result := e.ExecuteScenario(context.Background(), config, spec)
+
+	if len(result.Steps) != 2 {
+		t.Fatalf("expected 2 step results, got %+v", result.Steps)
+	}
+	if s := result.Steps[0]; s.Name != "login" || s.RequestsCompleted != 1 || s.Failed() {
+		t.Errorf("step 0 = %+v, want login with 1 completed request", s)
+	}
+	if s := result.Steps[1]; s.Name != "step-2" || s.RequestsCompleted != 1 || s.RequestsFailed != 1 || !s.Failed() {
+		t.Errorf("step 1 = %+v, want step-2 with 1 completed and 1 failed request", s)
+	}
+	requests := result.Steps[1].Requests
+	if len(requests) != 2 {
+		t.Fatalf("step 1 requests = %+v, want 2", requests)
+	}
+	if r := requests[0]; r.Name != "step-2/req-1" || r.RequestsCompleted != 1 || r.RequestsFailed != 0 ||
+		!maps.Equal(r.StatusCodes, map[string]int{"200": 1}) {
+		t.Errorf("step 1 request 0 = %+v, want step-2/req-1 with one 200", r)
+	}
+	if r := requests[1]; r.Name != "step-2/req-2" || r.RequestsCompleted != 0 || r.RequestsFailed != 1 ||
+		!maps.Equal(r.StatusCodes, map[string]int{"500": 1}) || len(r.Errors) != 0 {
+		t.Errorf("step 1 request 1 = %+v, want step-2/req-2 with one 500", r)
+	}
+	for _, r := range requests {
+		if r.FirstLatency <= 0 || r.LatencyP50 != r.FirstLatency || r.LatencyP99 != r.FirstLatency {
+			t.Errorf("request %s latencies = %v/%v/%v, want the single response's latency", r.Name, r.FirstLatency, r.LatencyP50, r.LatencyP99)
+		}
+	}
+	if s := result.Steps[1]; s.LatencyP50 <= 0 || s.LatencyP99 < s.LatencyP50 {
+		t.Errorf("step 1 latencies = %v/%v, want positive", s.LatencyP50, s.LatencyP99)
+	}
+	if result.LatencyP50 <= 0 || result.LatencyP99 < result.LatencyP50 {
+		t.Errorf("scenario latencies = %v/%v, want positive", result.LatencyP50, result.LatencyP99)
+	}
+	if first := result.Steps[0].Requests[0].FirstLatency; result.FirstRequestLatency != first || result.SpeedupRatio <= 0 {
+		t.Errorf("FirstRequestLatency = %v, SpeedupRatio = %v; want the login request's latency %v and a ratio",
+			result.FirstRequestLatency, result.SpeedupRatio, first)
+	}
+	if !strings.Contains(result.Message, "P50=") {
+		t.Errorf("Message = %q, want latency percentiles", result.Message)
+	}
+	if want := map[string]int{OutcomeSuccess: 2, "http_5xx": 1}; !maps.Equal(result.Outcomes, want) {
+		t.Errorf("Outcomes = %v, want %v", result.Outcomes, want)
+	}
+	if want := map[string]int{"http_5xx": 1}; !maps.Equal(requests[1].Outcomes, want) {
+		t.Errorf("step 1 request 1 Outcomes = %v, want %v", requests[1].Outcomes, want)
+	}
+}
+
+func TestScenarioExecutor_RequestErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	host, port := parseTestServerAddr(t, server.URL)
+	server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Name: "browse", Requests: []v1alpha1.WarmupRequest{{Name: "list", Endpoint: "/", Count: 5}}},
+		},
+	}
+	result := e.ExecuteScenario(context.Background(), newTestConfig(host, port), spec)
+
+	if len(result.Steps) != 1 || len(result.Steps[0].Requests) != 1 {
+		t.Fatalf("Steps = %+v, want one step with one request", result.Steps)
+	}
+	r := result.Steps[0].Requests[0]
+	if r.RequestsFailed != 5 || len(r.StatusCodes) != 0 || r.FirstLatency != 0 {
+		t.Errorf("request = %+v, want 5 failures without responses", r)
+	}
+	if len(r.Errors) == 0 || len(r.Errors) > maxRequestErrors {
+		t.Errorf("Errors = %v, want between 1 and %d distinct errors", r.Errors, maxRequestErrors)
+	}
+	if !strings.Contains(result.Message, "failed requests: list (5/5 failed, error: ") {
+		t.Errorf("Message = %q, want the failed request and its error", result.Message)
+	}
+	if want := map[string]int{OutcomeConnRefused: 5}; !maps.Equal(result.Outcomes, want) {
+		t.Errorf("Outcomes = %v, want %v", result.Outcomes, want)
+	}
+}
+
 func TestScenarioExecutor_StepTimeout(t *testing.T) {
 	// Step 1 blocks indefinitely; step 2 should still execute after step timeout.
 	blocked := make(chan struct{})
@@ -367,6 +872,192 @@ func TestScenarioExecutor_OverallTimeout(t *testing.T) {
 	}
 }
 
+func TestScenarioExecutor_Mix_WeightedSampling(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{{
+			Name: "traffic-profile",
+			Mix: &v1alpha1.WarmupMix{
+				TotalRequests: 1000,
+				Requests: []v1alpha1.WarmupRequest{
+					{Endpoint: "/browse", Weight: 6},
+					{Endpoint: "/search", Weight: 3},
+					{Endpoint: "/checkout", Weight: 1, Count: 50}, // Count is ignored in a mix
+				},
+			},
+		}},
+	}
+
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if result.RequestsCompleted != 1000 {
+		t.Fatalf("expected 1000 completed, got %d (failed %d)", result.RequestsCompleted, result.RequestsFailed)
+	}
+	// Expected shares are 600/300/100; allow wide margins to keep the test stable.
+	if hits["/browse"] < 500 || hits["/browse"] > 700 {
+		t.Errorf("/browse hits = %d, want ~600", hits["/browse"])
+	}
+	if hits["/search"] < 220 || hits["/search"] > 380 {
+		t.Errorf("/search hits = %d, want ~300", hits["/search"])
+	}
+	if hits["/checkout"] < 50 || hits["/checkout"] > 150 {
+		t.Errorf("/checkout hits = %d, want ~100", hits["/checkout"])
+	}
+}
+
+func TestScenarioExecutor_Mix_Duration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{{
+			Mix: &v1alpha1.WarmupMix{
+				Duration: "200ms",
+				Requests: []v1alpha1.WarmupRequest{{Endpoint: "/"}},
+			},
+		}},
+	}
+
+	start := time.Now()
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	elapsed := time.Since(start)
+
+	if elapsed < 150*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("mix ran for %v, want ~200ms", elapsed)
+	}
+	if result.RequestsCompleted == 0 {
+		t.Error("expected some completed requests during the mix duration")
+	}
+	if result.RequestsFailed != 0 {
+		t.Errorf("requests cut off by the mix duration should not count as failed, got %d", result.RequestsFailed)
+	}
+	if result.Error != nil {
+		t.Errorf("mix duration expiry should not surface as a scenario error, got %v", result.Error)
+	}
+}
+
+func TestScenarioExecutor_Mix_DefaultBudgetAndExtract(t *testing.T) {
+	var lastAuth string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"token":"t1"}`)) //nolint:errcheck // test handler
+	})
+	mux.HandleFunc("/browse", func(w http.ResponseWriter, r *http.Request) {
+		lastAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{
+			{Requests: []v1alpha1.WarmupRequest{{Endpoint: "/login", Extract: map[string]string{"token": "$.token"}}}},
+			{Mix: &v1alpha1.WarmupMix{
+				Requests: []v1alpha1.WarmupRequest{
+					{Endpoint: "/browse", Headers: map[string]string{"Authorization": "Bearer {{token}}"}},
+				},
+			}},
+		},
+	}
+
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if want := 1 + defaultMixRequests; result.RequestsCompleted != want {
+		t.Errorf("expected %d completed, got %d", want, result.RequestsCompleted)
+	}
+	if lastAuth != "Bearer t1" {
+		t.Errorf("mix request Authorization = %q, want %q", lastAuth, "Bearer t1")
+	}
+}
+
+func TestScenarioExecutor_Mix_InvalidDuration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	e := NewScenarioExecutor(ctrl.Log.WithName("test"))
+	host, port := parseTestServerAddr(t, server.URL)
+	config := newTestConfig(host, port)
+
+	// An unparsable duration falls back to the default request budget
+	spec := &v1alpha1.WarmupConfigSpec{
+		Steps: []v1alpha1.WarmupStep{{Mix: &v1alpha1.WarmupMix{
+			Duration: "forever",
+			Requests: []v1alpha1.WarmupRequest{{Endpoint: "/"}},
+		}}},
+	}
+
+	result := e.ExecuteScenario(context.Background(), config, spec)
+	if result.RequestsCompleted != defaultMixRequests || result.RequestsFailed != 0 {
+		t.Errorf("completed/failed = %d/%d, want %d/0", result.RequestsCompleted, result.RequestsFailed, defaultMixRequests)
+	}
+}
+
+func TestStepTimeout(t *testing.T) {
+	mix := func(duration string) *v1alpha1.WarmupMix {
+		return &v1alpha1.WarmupMix{Duration: duration, Requests: []v1alpha1.WarmupRequest{{}}}
+	}
+	staged := func(durations ...string) v1alpha1.WarmupRequest {
+		req := v1alpha1.WarmupRequest{}
+		for _, d := range durations {
+			req.Stages = append(req.Stages, v1alpha1.LoadStage{RPS: 5, Duration: d})
+		}
+		return req
+	}
+
+	tests := []struct {
+		name string
+		step v1alpha1.WarmupStep
+		want time.Duration
+	}{
+		{name: "default", step: v1alpha1.WarmupStep{}, want: defaultStepTimeout},
+		{name: "explicit", step: v1alpha1.WarmupStep{Timeout: "5s"}, want: 5 * time.Second},
+		{name: "invalid", step: v1alpha1.WarmupStep{Timeout: "soon"}, want: defaultStepTimeout},
+		{name: "long mix", step: v1alpha1.WarmupStep{Mix: mix("2m")}, want: 2*time.Minute + stepTimeoutGrace},
+		{name: "short mix", step: v1alpha1.WarmupStep{Mix: mix("5s")}, want: defaultStepTimeout},
+		{name: "mix with explicit timeout", step: v1alpha1.WarmupStep{Timeout: "45s", Mix: mix("2m")}, want: 45 * time.Second},
+		{name: "mix with invalid duration", step: v1alpha1.WarmupStep{Mix: mix("forever")}, want: defaultStepTimeout},
+		{
+			name: "long stages",
+			step: v1alpha1.WarmupStep{Requests: []v1alpha1.WarmupRequest{staged("20s", "20s", "20s"), {}, staged("30s")}},
+			want: 90*time.Second + stepTimeoutGrace,
+		},
+		{name: "short stages", step: v1alpha1.WarmupStep{Requests: []v1alpha1.WarmupRequest{staged("10s")}}, want: defaultStepTimeout},
+		{name: "stages with explicit timeout", step: v1alpha1.WarmupStep{Timeout: "45s", Requests: []v1alpha1.WarmupRequest{staged("1m")}}, want: 45 * time.Second},
+		{name: "invalid stages", step: v1alpha1.WarmupStep{Requests: []v1alpha1.WarmupRequest{staged("1m", "soon")}}, want: defaultStepTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stepTimeout(tt.step); got != tt.want {
+				t.Errorf("stepTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 // parseTestServerAddr extracts host and port as int from a test server URL.
 func parseTestServerAddr(t *testing.T, rawURL string) (string, int) {
 	t.Helper()
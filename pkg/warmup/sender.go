@@ -2,6 +2,7 @@ package warmup
 
 import (
 	"context"
+	"strconv"
 	"time"
 )
 
@@ -30,8 +31,14 @@ type Target struct {
 // Response is the outcome of a single Send call.
 type Response struct {
 	// StatusCode is the HTTP status code, or 200 (gRPC OK) / 500 (gRPC error).
+	// gRPC responses are judged by Status instead.
 	StatusCode int
 
+	// Status is the protocol status of a response that has one besides its HTTP
+	// status code, such as the status of a gRPC call that returned (including OK).
+	// It is nil for HTTP responses and for requests that failed with Error.
+	Status *Status
+
 	// Duration is the round-trip time from the start of Send to receiving the response.
 	Duration time.Duration
 
@@ -43,3 +50,21 @@ type Response struct {
 	// via StatusCode rather than Error so that latency is still recorded.
 	Error error
 }
+
+// Status is a protocol status, such as a gRPC status.
+type Status struct {
+	// Code is the name of the status code, e.g. "OK" or "UNAVAILABLE" for gRPC.
+	Code string
+
+	// Message is the status message (may be empty).
+	Message string
+}
+
+// StatusName returns the code of the response's Status, or its HTTP status code if
+// it has no Status, e.g. "UNAVAILABLE" or "503".
+func (r *Response) StatusName() string {
+	if r.Status != nil {
+		return r.Status.Code
+	}
+	return strconv.Itoa(r.StatusCode)
+}
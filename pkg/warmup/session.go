@@ -37,6 +37,23 @@ func (sc *SessionContext) Get(key string) (any, bool) {
 	return v, ok
 }
 
+// Values returns the values stored under keys. Keys that are not set are omitted.
+// It returns nil if none of the keys are set. Safe for concurrent use.
+func (sc *SessionContext) Values(keys []string) map[string]any {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	var values map[string]any
+	for _, k := range keys {
+		if v, ok := sc.data[k]; ok {
+			if values == nil {
+				values = make(map[string]any, len(keys))
+			}
+			values[k] = v
+		}
+	}
+	return values
+}
+
 // Interpolate replaces every {{varName}} token in s with the string representation
 // of the corresponding session value. Tokens referencing unknown keys are left as-is.
 // Keys are processed in sorted order so that a substituted value can never introduce
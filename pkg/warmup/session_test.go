@@ -52,6 +52,20 @@ func TestSessionContext_Interpolate(t *testing.T) {
 	}
 }
 
+func TestSessionContext_Values(t *testing.T) {
+	sc := NewSessionContext()
+	sc.Set("region", "eu")
+	sc.Set("token", "secret")
+
+	got := sc.Values([]string{"region", "missing"})
+	if len(got) != 1 || got["region"] != "eu" {
+		t.Errorf("Values() = %v, want map[region:eu]", got)
+	}
+	if got := sc.Values([]string{"missing"}); got != nil {
+		t.Errorf("Values() with no set keys = %v, want nil", got)
+	}
+}
+
 func TestSessionContext_ConcurrentAccess(t *testing.T) {
 	sc := NewSessionContext()
 	const workers = 50
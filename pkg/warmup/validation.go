@@ -0,0 +1,243 @@
+package warmup
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+var (
+	// variableToken matches a {{name}} interpolation token.
+	variableToken = regexp.MustCompile(`\{\{([^{}]*)\}\}`)
+
+	// httpToken matches a valid HTTP method (an RFC 9110 token).
+	httpToken = regexp.MustCompile("^[!#$%&'*+.^_`|~0-9A-Za-z-]+$")
+
+	standardMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+	}
+)
+
+// ValidatePod checks the warmup settings of a pod at admission. err is the error
+// that would make the controller skip the warmup once the pod's containers are
+// ready (see ParseConfigWithDefaults). warnings describe settings the pod sets that
+// are accepted but have no effect.
+func ValidatePod(pod *corev1.Pod, defaults webhook.Defaults) (warnings []string, err error) {
+	config, err := ParseConfigWithDefaults(pod, defaults)
+	if err != nil {
+		return nil, err
+	}
+
+	ignored := func(key, reason string) {
+		if pod.Annotations[key] != "" {
+			warnings = append(warnings, fmt.Sprintf("annotation %s is ignored %s", key, reason))
+		}
+	}
+	if config.WarmupConfigName != "" {
+		for _, key := range []string{
+			webhook.AnnotationWarmupEndpoint, webhook.AnnotationWarmupRequests, webhook.AnnotationWarmupStages,
+			webhook.AnnotationWarmupGRPCMethod, webhook.AnnotationWarmupGRPCPayload,
+		} {
+			ignored(key, "when "+webhook.AnnotationWarmupConfig+" is set")
+		}
+		return warnings, nil
+	}
+
+	if config.Protocol == ProtocolGRPC {
+		ignored(webhook.AnnotationWarmupEndpoint, "for gRPC warmup")
+	} else {
+		ignored(webhook.AnnotationWarmupGRPCMethod, "for HTTP warmup")
+		ignored(webhook.AnnotationWarmupGRPCPayload, "for HTTP warmup")
+	}
+	if len(config.Stages) > 0 {
+		ignored(webhook.AnnotationWarmupRequests, "when "+webhook.AnnotationWarmupStages+" is set")
+	}
+	if len(config.Params) > 0 {
+		warnings = append(warnings, fmt.Sprintf("WarmupConfig parameters are ignored when %s is not set", webhook.AnnotationWarmupConfig))
+	}
+	return warnings, nil
+}
+
+// ValidateWarmupConfigSpec checks the fields of spec that the CRD schema cannot:
+// durations, HTTP methods, gRPC methods and payloads, JSONPath expressions and
+// load stages. These are errors, since the scenario executor would otherwise fall
+// back to defaults or fail at runtime.
+//
+// It also checks that every {{var}} reference is set by an extract rule of an
+// earlier request or is a declared parameter ({{params.<name>}}). Undefined
+// references are only warnings: a config that is included by others may use
+// variables and parameters of the configs that include it.
+func ValidateWarmupConfigSpec(spec *v1alpha1.WarmupConfigSpec, path *field.Path) (field.ErrorList, []string) {
+	v := &specValidator{defined: map[string]bool{}}
+	for _, p := range spec.Parameters {
+		v.defined[ParamVariablePrefix+p.Name] = true
+	}
+
+	if spec.Timeout != "" {
+		if d := v.duration(path.Child("timeout"), spec.Timeout); d > maxScenarioTimeout {
+			v.warn(path.Child("timeout"), fmt.Sprintf("%v exceeds the maximum of %v and is capped", d, maxScenarioTimeout))
+		}
+	}
+
+	for i, step := range spec.Steps {
+		stepPath := path.Child("steps").Index(i)
+		var timeout time.Duration
+		if step.Timeout != "" {
+			timeout = v.duration(stepPath.Child("timeout"), step.Timeout)
+		}
+		if step.Mix != nil {
+			mixPath := stepPath.Child("mix")
+			if step.Mix.Duration != "" {
+				if d := v.duration(mixPath.Child("duration"), step.Mix.Duration); timeout > 0 && d > timeout {
+					v.warn(mixPath.Child("duration"), fmt.Sprintf("%v exceeds the step timeout of %v and is cut short", d, timeout))
+				}
+			}
+			// Mix requests run in random order, so variables they extract are only
+			// known to be set for later steps.
+			var extracted []string
+			for j := range step.Mix.Requests {
+				extracted = append(extracted, v.request(mixPath.Child("requests").Index(j), &step.Mix.Requests[j])...)
+			}
+			v.define(extracted)
+			continue
+		}
+		for j := range step.Requests {
+			v.define(v.request(stepPath.Child("requests").Index(j), &step.Requests[j]))
+		}
+		if d := stagesDuration(step.Requests); timeout > 0 && d > timeout {
+			v.warn(stepPath.Child("timeout"), fmt.Sprintf("%v is shorter than the %v the step's load stages take, and they are cut short", timeout, d))
+		}
+	}
+	return v.errs, v.warnings
+}
+
+type specValidator struct {
+	errs     field.ErrorList
+	warnings []string
+	defined  map[string]bool // variables set by the requests validated so far
+}
+
+func (v *specValidator) warn(path *field.Path, msg string) {
+	v.warnings = append(v.warnings, fmt.Sprintf("%s: %s", path, msg))
+}
+
+func (v *specValidator) define(names []string) {
+	for _, name := range names {
+		v.defined[name] = true
+	}
+}
+
+// duration checks that s is a positive Go duration and returns it.
+func (v *specValidator) duration(path *field.Path, s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		v.errs = append(v.errs, field.Invalid(path, s, "must be a Go duration such as \"30s\" or \"2m\""))
+		return 0
+	}
+	if d <= 0 {
+		v.errs = append(v.errs, field.Invalid(path, s, "must be positive"))
+	}
+	return d
+}
+
+// request validates req and returns the names of the variables it extracts.
+func (v *specValidator) request(path *field.Path, req *v1alpha1.WarmupRequest) []string {
+	if req.Protocol == ProtocolGRPC && req.GRPCMethod == "" {
+		v.errs = append(v.errs, field.Required(path.Child("grpcMethod"), "required when protocol is \"grpc\""))
+	}
+	if req.GRPCMethod != "" {
+		if _, _, err := parseGRPCMethod(req.GRPCMethod); err != nil {
+			v.errs = append(v.errs, field.Invalid(path.Child("grpcMethod"), req.GRPCMethod, "must be in the form \"package.Service/Method\""))
+		}
+	}
+	if req.GRPCPayload != "" {
+		// Substitute the interpolation tokens so that unquoted {{var}} values parse
+		if !json.Valid([]byte(variableToken.ReplaceAllString(req.GRPCPayload, "0"))) {
+			v.errs = append(v.errs, field.Invalid(path.Child("grpcPayload"), req.GRPCPayload, "must be valid JSON"))
+		}
+	}
+
+	if req.Method != "" {
+		if !httpToken.MatchString(req.Method) {
+			v.errs = append(v.errs, field.Invalid(path.Child("method"), req.Method, "must be a valid HTTP method"))
+		} else if !slices.Contains(standardMethods, req.Method) {
+			v.warn(path.Child("method"), fmt.Sprintf("%q is not a standard HTTP method (methods are case-sensitive)", req.Method))
+		}
+	}
+	if req.ExpectedStatus != 0 && (req.ExpectedStatus < 100 || req.ExpectedStatus > 599) {
+		v.errs = append(v.errs, field.Invalid(path.Child("expectedStatus"), req.ExpectedStatus, "must be an HTTP status code (100-599)"))
+	}
+	if req.ExpectedGRPCCode != "" && !slices.Contains(grpcCodeNames[:], req.ExpectedGRPCCode) {
+		v.errs = append(v.errs, field.NotSupported(path.Child("expectedGRPCCode"), req.ExpectedGRPCCode, grpcCodeNames[:]))
+	}
+
+	for i, stage := range req.Stages {
+		stagePath := path.Child("stages").Index(i)
+		if stage.RPS < 1 {
+			v.errs = append(v.errs, field.Invalid(stagePath.Child("rps"), stage.RPS, "must be at least 1"))
+		}
+		if d := v.duration(stagePath.Child("duration"), stage.Duration); d > MaxTimeout {
+			v.errs = append(v.errs, field.Invalid(stagePath.Child("duration"), stage.Duration,
+				fmt.Sprintf("must not exceed %v", MaxTimeout)))
+		}
+	}
+
+	v.references(path.Child("endpoint"), req.Endpoint)
+	v.references(path.Child("body"), req.Body)
+	v.references(path.Child("grpcPayload"), req.GRPCPayload)
+	for _, name := range sortedKeys(req.Headers) {
+		v.references(path.Child("headers").Key(name), req.Headers[name])
+	}
+
+	var extracted []string
+	for _, name := range sortedKeys(req.Extract) {
+		extractPath := path.Child("extract").Key(name)
+		if _, err := parseJSONPath(req.Extract[name]); err != nil {
+			v.errs = append(v.errs, field.Invalid(extractPath, req.Extract[name], err.Error()))
+		}
+		if strings.HasPrefix(name, ParamVariablePrefix) {
+			v.errs = append(v.errs, field.Invalid(extractPath, name, fmt.Sprintf("variable names starting with %q are reserved for parameters", ParamVariablePrefix)))
+		}
+		if strings.ContainsAny(name, "{}") {
+			v.errs = append(v.errs, field.Invalid(extractPath, name, "variable names must not contain braces"))
+		}
+		extracted = append(extracted, name)
+	}
+	return extracted
+}
+
+// references warns about each {{var}} in s that is not defined yet.
+func (v *specValidator) references(path *field.Path, s string) {
+	for _, m := range variableToken.FindAllStringSubmatch(s, -1) {
+		name := m[1]
+		if v.defined[name] {
+			continue
+		}
+		if param, ok := strings.CutPrefix(name, ParamVariablePrefix); ok {
+			v.warn(path, fmt.Sprintf("{{%s}} refers to parameter %q, which is not declared in spec.parameters", name, param))
+			continue
+		}
+		v.warn(path, fmt.Sprintf("{{%s}} is not set by an extract rule of an earlier request and is sent as-is if still unset", name))
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
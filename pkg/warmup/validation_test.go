@@ -0,0 +1,312 @@
+package warmup
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/webhook"
+)
+
+func TestValidatePod(t *testing.T) {
+	onePort := []corev1.ContainerPort{{ContainerPort: 8080}}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		ports        []corev1.ContainerPort
+		defaults     webhook.Defaults
+		wantErr      string
+		wantWarnings []string
+	}{
+		{
+			name:        "valid",
+			annotations: map[string]string{webhook.AnnotationWarmupRequests: "5"},
+			ports:       onePort,
+		},
+		{
+			name:        "bad request count",
+			annotations: map[string]string{webhook.AnnotationWarmupRequests: "lots"},
+			ports:       onePort,
+			wantErr:     "invalid warmup-requests value",
+		},
+		{
+			name:    "multiple ports without warmup-port",
+			ports:   []corev1.ContainerPort{{ContainerPort: 8080}, {ContainerPort: 9090}},
+			wantErr: "multiple ports",
+		},
+		{
+			name:        "gRPC without method",
+			annotations: map[string]string{webhook.AnnotationWarmupProtocol: ProtocolGRPC},
+			ports:       onePort,
+			wantErr:     webhook.AnnotationWarmupGRPCMethod + " is required",
+		},
+		{
+			name: "bad setting inherited from the namespace",
+			defaults: webhook.Defaults{Namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{webhook.AnnotationWarmupTimeout: "forever"},
+			}}},
+			ports:   onePort,
+			wantErr: "invalid warmup-timeout value",
+		},
+		{
+			name: "settings overridden by a WarmupConfig",
+			annotations: map[string]string{
+				webhook.AnnotationWarmupConfig:   "checkout",
+				webhook.AnnotationWarmupEndpoint: "/warmup",
+				webhook.AnnotationWarmupRequests: "5",
+			},
+			ports: onePort,
+			wantWarnings: []string{
+				"annotation kube-booster.io/warmup-endpoint is ignored when kube-booster.io/warmup-config is set",
+				"annotation kube-booster.io/warmup-requests is ignored when kube-booster.io/warmup-config is set",
+			},
+		},
+		{
+			name: "settings of the other protocol",
+			annotations: map[string]string{
+				webhook.AnnotationWarmupEndpoint:   "/warmup",
+				webhook.AnnotationWarmupGRPCMethod: "shop.Catalog/List",
+				webhook.AnnotationWarmupStages:     "5:10s",
+				webhook.AnnotationWarmupRequests:   "5",
+				"kube-booster.io/param.tenant":     "acme",
+			},
+			ports: onePort,
+			wantWarnings: []string{
+				"annotation kube-booster.io/warmup-grpc-method is ignored for HTTP warmup",
+				"annotation kube-booster.io/warmup-requests is ignored when kube-booster.io/warmup-stages is set",
+				"WarmupConfig parameters are ignored when kube-booster.io/warmup-config is not set",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", Annotations: tt.annotations},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Ports: tt.ports}}},
+			}
+
+			warnings, err := ValidatePod(pod, tt.defaults)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ValidatePod() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidatePod() unexpected error: %v", err)
+			}
+			if strings.Join(warnings, "\n") != strings.Join(tt.wantWarnings, "\n") {
+				t.Errorf("ValidatePod() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+// TestValidatePod_Admission runs ValidatePod through the pod webhook in deny mode, so
+// that the settings a pod inherits are validated like the controller applies them.
+func TestValidatePod_Admission(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	policy := &v1alpha1.WarmupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: v1alpha1.WarmupPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Port:        9090,
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, policy).Build()
+
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		wantAllowed bool
+	}{
+		{name: "policy supplies the port", labels: map[string]string{"app": "web"}, wantAllowed: true},
+		{name: "no port", labels: map[string]string{"app": "batch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutator := webhook.NewPodMutator(c, scheme)
+			mutator.Validate = ValidatePod
+			mutator.DenyInvalid = true
+
+			// The pod enables warmup itself, but cannot tell which of its ports to warm up
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "web-1",
+					Namespace:   "default",
+					Labels:      tt.labels,
+					Annotations: map[string]string{webhook.AnnotationWarmupEnabled: webhook.WarmupEnabledValue},
+				},
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{
+					Name:  "app",
+					Ports: []corev1.ContainerPort{{ContainerPort: 8080}, {ContainerPort: 9090}},
+				}}},
+			}
+			raw, err := json.Marshal(pod)
+			if err != nil {
+				t.Fatalf("failed to marshal pod: %v", err)
+			}
+			req := admission.Request{}
+			req.Namespace = "default"
+			req.Object = runtime.RawExtension{Raw: raw}
+
+			resp := mutator.Handle(context.Background(), req)
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("Handle() allowed = %v, want %v (%v)", resp.Allowed, tt.wantAllowed, resp.Result)
+			}
+			if tt.wantAllowed && len(resp.Warnings) > 0 {
+				t.Errorf("Handle() warnings = %q, want none", resp.Warnings)
+			}
+		})
+	}
+}
+
+func TestValidateWarmupConfigSpec(t *testing.T) {
+	requests := func(reqs ...v1alpha1.WarmupRequest) v1alpha1.WarmupStep {
+		return v1alpha1.WarmupStep{Requests: reqs}
+	}
+
+	tests := []struct {
+		name         string
+		spec         v1alpha1.WarmupConfigSpec
+		wantErrs     []string // field paths
+		wantWarnings []string // substrings, in order
+	}{
+		{
+			name: "valid scenario",
+			spec: v1alpha1.WarmupConfigSpec{
+				Timeout:    "2m",
+				Parameters: []v1alpha1.WarmupParameter{{Name: "tenant"}},
+				Steps: []v1alpha1.WarmupStep{
+					requests(v1alpha1.WarmupRequest{
+						Endpoint: "/login",
+						Method:   "POST",
+						Body:     `{"tenant":"{{params.tenant}}"}`,
+						Extract:  map[string]string{"token": "$.session.token"},
+					}),
+					{Timeout: "10s", Requests: []v1alpha1.WarmupRequest{{
+						Protocol:         ProtocolGRPC,
+						GRPCMethod:       "shop.Catalog/List",
+						GRPCPayload:      `{"token":"{{token}}","limit":{{params.tenant}}}`,
+						ExpectedGRPCCode: "NOT_FOUND",
+						Stages:           []v1alpha1.LoadStage{{RPS: 5, Duration: "10s"}},
+					}}},
+				},
+			},
+		},
+		{
+			name: "bad durations",
+			spec: v1alpha1.WarmupConfigSpec{
+				Timeout: "2 minutes",
+				Steps: []v1alpha1.WarmupStep{
+					{Timeout: "-5s", Requests: []v1alpha1.WarmupRequest{{Stages: []v1alpha1.LoadStage{
+						{RPS: 5, Duration: "10"},
+						{RPS: 0, Duration: "10s"},
+						{RPS: 5, Duration: "10m"},
+					}}}},
+					{Mix: &v1alpha1.WarmupMix{Duration: "forever", Requests: []v1alpha1.WarmupRequest{{}}}},
+				},
+			},
+			wantErrs: []string{
+				"spec.timeout",
+				"spec.steps[0].timeout",
+				"spec.steps[0].requests[0].stages[0].duration",
+				"spec.steps[0].requests[0].stages[1].rps",
+				"spec.steps[0].requests[0].stages[2].duration",
+				"spec.steps[1].mix.duration",
+			},
+		},
+		{
+			name: "bad requests",
+			spec: v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{requests(
+				v1alpha1.WarmupRequest{Protocol: ProtocolGRPC, ExpectedGRPCCode: "NotFound"},
+				v1alpha1.WarmupRequest{GRPCMethod: "List", GRPCPayload: `{"limit":`},
+				v1alpha1.WarmupRequest{Method: "GET /", ExpectedStatus: 1000},
+				v1alpha1.WarmupRequest{Extract: map[string]string{"items": "$.items[0]", "params.x": "token", "a": "$.a..b"}},
+			)}},
+			wantErrs: []string{
+				"spec.steps[0].requests[0].grpcMethod",
+				"spec.steps[0].requests[0].expectedGRPCCode",
+				"spec.steps[0].requests[1].grpcMethod",
+				"spec.steps[0].requests[1].grpcPayload",
+				"spec.steps[0].requests[2].method",
+				"spec.steps[0].requests[2].expectedStatus",
+				"spec.steps[0].requests[3].extract[a]",
+				"spec.steps[0].requests[3].extract[items]",
+				"spec.steps[0].requests[3].extract[params.x]",
+				"spec.steps[0].requests[3].extract[params.x]",
+			},
+		},
+		{
+			name: "undefined variables warn",
+			spec: v1alpha1.WarmupConfigSpec{
+				Timeout: "10m",
+				Steps: []v1alpha1.WarmupStep{
+					requests(
+						v1alpha1.WarmupRequest{Headers: map[string]string{"Authorization": "Bearer {{token}}"}},
+						v1alpha1.WarmupRequest{Endpoint: "/{{params.region}}", Extract: map[string]string{"token": "$.token"}},
+						v1alpha1.WarmupRequest{Method: "PURGE", Body: "{{token}}"},
+					),
+					{Mix: &v1alpha1.WarmupMix{Requests: []v1alpha1.WarmupRequest{
+						{Extract: map[string]string{"cart": "$.id"}},
+						{Endpoint: "/cart/{{cart}}"},
+					}}},
+					requests(v1alpha1.WarmupRequest{Endpoint: "/cart/{{cart}}"}),
+					{Timeout: "30s", Mix: &v1alpha1.WarmupMix{Duration: "2m", Requests: []v1alpha1.WarmupRequest{{}}}},
+					{Timeout: "45s", Requests: []v1alpha1.WarmupRequest{
+						{Stages: []v1alpha1.LoadStage{{RPS: 5, Duration: "20s"}, {RPS: 10, Duration: "20s"}}},
+						{Stages: []v1alpha1.LoadStage{{RPS: 5, Duration: "20s"}}},
+					}},
+				},
+			},
+			wantWarnings: []string{
+				"spec.timeout: 10m0s exceeds the maximum",
+				"spec.steps[0].requests[0].headers[Authorization]: {{token}} is not set",
+				`spec.steps[0].requests[1].endpoint: {{params.region}} refers to parameter "region", which is not declared`,
+				`spec.steps[0].requests[2].method: "PURGE" is not a standard HTTP method`,
+				"spec.steps[1].mix.requests[1].endpoint: {{cart}} is not set",
+				"spec.steps[3].mix.duration: 2m0s exceeds the step timeout of 30s",
+				"spec.steps[4].timeout: 45s is shorter than the 1m0s the step's load stages take",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs, warnings := ValidateWarmupConfigSpec(&tt.spec, field.NewPath("spec"))
+
+			var gotErrs []string
+			for _, err := range errs {
+				gotErrs = append(gotErrs, err.Field)
+			}
+			if strings.Join(gotErrs, ",") != strings.Join(tt.wantErrs, ",") {
+				t.Errorf("error fields = %v, want %v\nerrors: %v", gotErrs, tt.wantErrs, errs)
+			}
+
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("warnings = %q, want %d", warnings, len(tt.wantWarnings))
+			}
+			for i, want := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warnings[%d] = %q, want it to contain %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
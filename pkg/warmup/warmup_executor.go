@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+	"github.com/hhiroshell/kube-booster/pkg/signing"
 )
 
 // Executor executes warmup requests
@@ -27,12 +30,21 @@ func WithRateLimiter(rl *RequestRateLimiter) WarmupExecutorOption {
 	}
 }
 
+// WithSigner signs every warmup request with the given signer.
+// Passing nil disables signing.
+func WithSigner(s *signing.Signer) WarmupExecutorOption {
+	return func(e *WarmupExecutor) {
+		e.signer = s
+	}
+}
+
 // WarmupExecutor fires warmup requests back-to-back (ASAP model), dispatching to the
 // appropriate Sender based on the configured protocol (HTTP or gRPC).
 type WarmupExecutor struct {
 	logger      logr.Logger
 	client      *http.Client
 	rateLimiter *RequestRateLimiter // nil = unlimited
+	signer      *signing.Signer     // nil = requests are not signed
 }
 
 // NewWarmupExecutor creates a new WarmupExecutor.
@@ -49,7 +61,8 @@ func NewWarmupExecutor(logger logr.Logger, opts ...WarmupExecutorOption) *Warmup
 	return e
 }
 
-// Execute performs warmup requests back-to-back as fast as possible.
+// Execute performs warmup requests back-to-back as fast as possible, or paced by the
+// configured load stages.
 func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 	result := &Result{}
 
@@ -66,14 +79,16 @@ func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 	)
 	switch config.Protocol {
 	case ProtocolGRPC:
-		sender = NewGRPCSender(e.logger)
+		grpcSender := NewGRPCSender(e.logger)
+		grpcSender.signer = e.signer
+		sender = grpcSender
 		target = Target{
 			Address: config.BuildGRPCAddress(),
 			Method:  config.GRPCMethod,
 			Payload: []byte(config.GRPCPayload),
 		}
 	case ProtocolHTTP:
-		sender = &HTTPSender{client: e.client, logger: e.logger}
+		sender = &HTTPSender{client: e.client, logger: e.logger, signer: e.signer}
 		target = Target{
 			Address: config.BuildEndpointURL(),
 			Method:  http.MethodGet,
@@ -94,6 +109,8 @@ func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 		"address", target.Address,
 		"method", target.Method,
 		"requestCount", config.RequestCount,
+		"stages", len(config.Stages),
+		"rps", config.RPS,
 		"timeout", config.Timeout)
 	defer sender.Close() //nolint:errcheck
 
@@ -106,19 +123,37 @@ func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 	successCount := 0
 	failCount := 0
 
-	for i := 0; i < config.RequestCount; i++ {
+	// With load stages, the stages decide how long the warmup runs instead of RequestCount.
+	stages := NewStageRateLimiter(config.Stages)
+	// The per-pod limiter only governs this execution; the shared one spans all pods.
+	podLimiter := NewRequestRateLimiter(config.RPS)
+
+	for i := 0; stages != nil || i < config.RequestCount; i++ {
 		if warmupCtx.Err() != nil {
 			break
 		}
 
-		if err := e.rateLimiter.Wait(warmupCtx); err != nil {
+		// The per-pod stage and rate limiters are layered under the shared limiter so
+		// that a pod never consumes global tokens faster than its own limits allow.
+		if err := stages.Wait(warmupCtx); err != nil {
+			// ErrStagesComplete ends a staged warmup normally.
+			break
+		}
+
+		if err := waitAll(warmupCtx, podLimiter, e.rateLimiter); err != nil {
 			// Count remaining un-attempted requests as failed so the result message and
 			// the fail-open decision in the controller reflect the full RequestCount picture.
-			failCount += config.RequestCount - successCount - failCount
+			if stages == nil {
+				failCount += config.RequestCount - successCount - failCount
+			}
 			break
 		}
 
 		resp := sender.Send(warmupCtx, target)
+		// Judged like a scenario request with no expected status: a gRPC call by
+		// its status code, which must be OK
+		ok := resp.Error == nil && isSuccess(resp, v1alpha1.WarmupRequest{}, config.Protocol)
+		result.countOutcomes(ClassifyResponse(resp, ok), 1)
 
 		if resp.Error != nil {
 			failCount++
@@ -132,10 +167,11 @@ func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 		// Even error responses exercise the application's request handling path.
 		latencies = append(latencies, resp.Duration)
 
-		if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		if ok {
 			successCount++
 		} else {
 			failCount++
+			e.logger.V(2).Info("warmup request returned unexpected status", "status", resp.StatusName())
 		}
 	}
 
@@ -145,6 +181,8 @@ func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 		result.Error = warmupCtx.Err()
 	}
 
+	// Measured before the percentiles, which sort latencies
+	result.measureEffectiveness(latencies)
 	p50, p99 := calculatePercentiles(latencies)
 
 	result.RequestsCompleted = successCount
@@ -164,6 +202,9 @@ func (e *WarmupExecutor) Execute(ctx context.Context, config *Config) *Result {
 		"requestsFailed", failCount,
 		"latencyP50", p50,
 		"latencyP99", p99,
+		"firstRequestLatency", result.FirstRequestLatency,
+		"speedupRatio", result.SpeedupRatio,
+		"outcomes", result.Outcomes,
 		"duration", totalDuration)
 
 	return result
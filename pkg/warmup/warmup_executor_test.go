@@ -2,9 +2,11 @@ package warmup
 
 import (
 	"context"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -198,6 +200,81 @@ func TestWarmupExecutor_Execute_MetricsCollection(t *testing.T) {
 	}
 }
 
+func TestWarmupExecutor_Execute_Outcomes(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 3:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	parts := strings.Split(server.Listener.Addr().String(), ":")
+	config := &Config{
+		Endpoint:     "/",
+		RequestCount: 5,
+		Timeout:      10 * time.Second,
+		Protocol:     ProtocolHTTP,
+		PodIP:        parts[0],
+		Port:         parsePort(parts[1]),
+	}
+
+	result := NewWarmupExecutor(ctrl.Log.WithName("test")).Execute(context.Background(), config)
+
+	want := map[string]int{OutcomeRateLimited: 1, "http_5xx": 1, "http_4xx": 1, OutcomeSuccess: 2}
+	if !maps.Equal(result.Outcomes, want) {
+		t.Errorf("Outcomes = %v, want %v", result.Outcomes, want)
+	}
+}
+
+func TestWarmupExecutor_Execute_ColdStart(t *testing.T) {
+	logger := ctrl.Log.WithName("test")
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// Cold start
+			time.Sleep(100 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().String()
+	parts := strings.Split(addr, ":")
+	config := &Config{
+		Endpoint:     "/",
+		RequestCount: 10,
+		Timeout:      10 * time.Second,
+		Protocol:     ProtocolHTTP,
+		GRPCPayload:  DefaultGRPCPayload,
+		PodIP:        parts[0],
+		Port:         parsePort(parts[1]),
+		PodName:      "test-pod",
+		PodNamespace: "default",
+	}
+
+	result := NewWarmupExecutor(logger).Execute(context.Background(), config)
+
+	if result.FirstRequestLatency < 100*time.Millisecond {
+		t.Errorf("FirstRequestLatency = %v, want at least 100ms", result.FirstRequestLatency)
+	}
+	if result.FinalWindowLatency <= 0 || result.FinalWindowLatency >= result.FirstRequestLatency {
+		t.Errorf("FinalWindowLatency = %v, want positive and below the first request's", result.FinalWindowLatency)
+	}
+	if result.SpeedupRatio <= 1 {
+		t.Errorf("SpeedupRatio = %v, want > 1", result.SpeedupRatio)
+	}
+	if !strings.Contains(result.Message, "speedup=") {
+		t.Errorf("Message = %q, want the speedup", result.Message)
+	}
+}
+
 func TestWarmupExecutor_Execute_GRPC(t *testing.T) {
 	logger := ctrl.Log.WithName("test")
 
@@ -256,6 +333,33 @@ func TestWarmupExecutor_Execute_GRPC_NoReflection(t *testing.T) {
 	// No panic: executor applied fail-open (no crash, result returned).
 }
 
+func TestWarmupExecutor_Execute_GRPC_Status(t *testing.T) {
+	addr, stop := startTestGRPCServer(t, true)
+	defer stop()
+
+	parts := strings.Split(addr, ":")
+	config := &Config{
+		RequestCount: 3,
+		Timeout:      10 * time.Second,
+		Protocol:     ProtocolGRPC,
+		GRPCMethod:   "grpc.health.v1.Health/Check",
+		// The health server answers NOT_FOUND for an unknown service
+		GRPCPayload: `{"service":"unknown"}`,
+		PodIP:       parts[0],
+		Port:        parsePort(parts[1]),
+	}
+
+	result := NewWarmupExecutor(ctrl.Log.WithName("test")).Execute(context.Background(), config)
+
+	if result.Success || result.RequestsFailed != 3 {
+		t.Errorf("Success = %v, RequestsFailed = %d; want false, 3", result.Success, result.RequestsFailed)
+	}
+	want := map[string]int{"grpc_not_found": 3}
+	if !maps.Equal(result.Outcomes, want) {
+		t.Errorf("Outcomes = %v, want %v", result.Outcomes, want)
+	}
+}
+
 func TestCalculatePercentiles(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -316,3 +420,88 @@ func parsePort(s string) int {
 	}
 	return port
 }
+
+func TestWarmupExecutor_Execute_Stages(t *testing.T) {
+	logger := ctrl.Log.WithName("test")
+
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().String()
+	parts := strings.Split(addr, ":")
+	config := &Config{
+		Endpoint:     "/",
+		RequestCount: 1, // ignored when stages are set
+		Timeout:      10 * time.Second,
+		Protocol:     ProtocolHTTP,
+		GRPCPayload:  DefaultGRPCPayload,
+		PodIP:        parts[0],
+		Port:         parsePort(parts[1]),
+		PodName:      "test-pod",
+		PodNamespace: "default",
+		Stages: []Stage{
+			{RPS: 20, Duration: 200 * time.Millisecond},
+			{RPS: 50, Duration: 200 * time.Millisecond},
+		},
+	}
+
+	executor := NewWarmupExecutor(logger)
+	start := time.Now()
+	result := executor.Execute(context.Background(), config)
+	elapsed := time.Since(start)
+
+	if !result.Success || result.Error != nil {
+		t.Fatalf("Execute() Success = %v, Error = %v, want success", result.Success, result.Error)
+	}
+	if elapsed < 400*time.Millisecond {
+		t.Errorf("Execute() finished after %v, want >= 400ms", elapsed)
+	}
+	// About 4 requests in the first stage and 10 in the second.
+	if got := hits.Load(); got < 8 || got > 20 {
+		t.Errorf("requests sent = %d, want about 14", got)
+	}
+	if result.RequestsFailed != 0 {
+		t.Errorf("RequestsFailed = %d, want 0", result.RequestsFailed)
+	}
+}
+
+func TestWarmupExecutor_Execute_PerPodRateLimit(t *testing.T) {
+	logger := ctrl.Log.WithName("test")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	addr := server.Listener.Addr().String()
+	parts := strings.Split(addr, ":")
+	config := &Config{
+		Endpoint:     "/",
+		RequestCount: 4,
+		Timeout:      10 * time.Second,
+		Protocol:     ProtocolHTTP,
+		GRPCPayload:  DefaultGRPCPayload,
+		PodIP:        parts[0],
+		Port:         parsePort(parts[1]),
+		PodName:      "test-pod",
+		PodNamespace: "default",
+		RPS:          2,
+	}
+
+	// The global limiter is generous; the per-pod limit must still apply.
+	executor := NewWarmupExecutor(logger, WithRateLimiter(NewRequestRateLimiter(1000)))
+	start := time.Now()
+	result := executor.Execute(context.Background(), config)
+
+	if result.RequestsCompleted != 4 {
+		t.Errorf("RequestsCompleted = %d, want 4", result.RequestsCompleted)
+	}
+	// Burst of 2, then two more requests at 2 rps.
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("Execute() finished after %v, want >= ~1s", elapsed)
+	}
+}
@@ -25,6 +25,27 @@ const (
 	// AnnotationWarmupGRPCPayload is the annotation key to specify the gRPC request payload (JSON)
 	AnnotationWarmupGRPCPayload = "kube-booster.io/warmup-grpc-payload"
 
+	// AnnotationWarmupStages is the annotation key to specify ramp-up load stages
+	// as comma-separated "<rps>:<duration>" pairs (e.g. "5:10s,20:10s,50:20s")
+	AnnotationWarmupStages = "kube-booster.io/warmup-stages"
+
+	// AnnotationWarmupRPS is the annotation key to cap this pod's warmup request rate
+	// (requests per second, may be fractional)
+	AnnotationWarmupRPS = "kube-booster.io/warmup-rps"
+
+	// AnnotationWarmupProgress is the annotation key the controller uses to hand off
+	// an interrupted warmup (JSON-encoded warmup.Progress) to the next controller instance
+	AnnotationWarmupProgress = "kube-booster.io/warmup-progress"
+
+	// AnnotationWarmupResult is the annotation key the controller uses to record the
+	// outcome of a pod's WarmupConfig warmup (JSON-encoded warmup.RunSummary), from
+	// which the WarmupConfig status is computed
+	AnnotationWarmupResult = "kube-booster.io/warmup-result"
+
+	// LabelWarmupRunPodUID is the label key set on WarmupRun objects to the UID of the
+	// pod they record, alongside the pod's own labels
+	LabelWarmupRunPodUID = "kube-booster.io/pod-uid"
+
 	// ReadinessGateName is the name of the readiness gate injected into pods
 	ReadinessGateName = "kube-booster.io/warmup-ready"
 
@@ -34,8 +55,24 @@ const (
 	// AnnotationWarmupConfig is the annotation key referencing a WarmupConfig CR by name.
 	// When set, the controller fetches the named WarmupConfig from the pod's namespace
 	// and uses scenario-based warmup instead of the annotation-based single-endpoint warmup.
+	// A "ClusterWarmupConfig/<name>" value references a ClusterWarmupConfig instead.
 	AnnotationWarmupConfig = "kube-booster.io/warmup-config"
 
+	// AnnotationWarmupConfigParams is the annotation key to set the parameters of the
+	// referenced WarmupConfig as a JSON object (e.g. {"tenant":"acme","region":"eu"})
+	AnnotationWarmupConfigParams = "kube-booster.io/warmup-config-params"
+
+	// AnnotationWarmupParamPrefix prefixes annotation keys that set a single WarmupConfig
+	// parameter (e.g. kube-booster.io/param.tenant). They take precedence over
+	// AnnotationWarmupConfigParams.
+	AnnotationWarmupParamPrefix = "kube-booster.io/param."
+
+	// KindWarmupConfig is the kind of namespaced warmup scenarios
+	KindWarmupConfig = "WarmupConfig"
+
+	// KindClusterWarmupConfig is the kind of cluster-scoped warmup scenarios
+	KindClusterWarmupConfig = "ClusterWarmupConfig"
+
 	// WarmupEnabledValue is the value that enables warmup
 	WarmupEnabledValue = "enabled"
 )
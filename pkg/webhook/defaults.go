@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SettingSourcePod marks a setting taken from the pod's own annotations
+	SettingSourcePod = "pod annotation"
+
+	// SettingSourceNamespace marks a setting taken from the pod's namespace
+	SettingSourceNamespace = "namespace"
+)
+
+// NamespaceDefaultAnnotations lists the annotations a namespace may set to provide
+// defaults for its pods. A pod annotation with a non-empty value always wins.
+var NamespaceDefaultAnnotations = []string{
+	AnnotationWarmupEndpoint,
+	AnnotationWarmupRequests,
+	AnnotationWarmupTimeout,
+	AnnotationWarmupConfig,
+}
+
+// Defaults are where a pod inherits warmup settings from when its own annotations
+// do not set them. Settings are taken from the pod first, then the policy, then the
+// namespace. Both fields may be nil.
+type Defaults struct {
+	// Policy is the WarmupPolicy or ClusterWarmupPolicy that selects the pod
+	Policy *MatchedPolicy
+
+	// Namespace is the pod's namespace
+	Namespace *corev1.Namespace
+}
+
+// WarmupEnabled reports whether warmup is enabled for pod and which source decided
+// it. A pod annotation (any value) takes precedence; otherwise warmup is enabled if
+// a policy selects the pod, or if the namespace carries
+// AnnotationWarmupEnabled=WarmupEnabledValue as a label or an annotation. The
+// source is empty when warmup is not enabled by any of them.
+func (d Defaults) WarmupEnabled(pod *corev1.Pod) (bool, string) {
+	if pod != nil {
+		if value, ok := pod.Annotations[AnnotationWarmupEnabled]; ok {
+			return value == WarmupEnabledValue, SettingSourcePod
+		}
+	}
+	if d.Policy != nil {
+		return true, d.Policy.String()
+	}
+	if ns := d.Namespace; ns != nil && (ns.Labels[AnnotationWarmupEnabled] == WarmupEnabledValue ||
+		ns.Annotations[AnnotationWarmupEnabled] == WarmupEnabledValue) {
+		return true, SettingSourceNamespace
+	}
+	return false, ""
+}
+
+// EffectiveAnnotations returns the pod's annotations layered over the policy
+// settings and the namespace defaults listed in NamespaceDefaultAnnotations, along
+// with the source of each setting that is set by the pod or inherited. The pod is
+// not modified.
+func (d Defaults) EffectiveAnnotations(pod *corev1.Pod) (map[string]string, map[string]string) {
+	annotations := map[string]string{}
+	if pod != nil {
+		for k, v := range pod.Annotations {
+			annotations[k] = v
+		}
+	}
+
+	sources := map[string]string{}
+	inherit := func(key, value, source string) {
+		if _, ok := sources[key]; ok || value == "" {
+			return
+		}
+		if annotations[key] != "" {
+			sources[key] = SettingSourcePod
+			return
+		}
+		annotations[key] = value
+		sources[key] = source
+	}
+	if d.Policy != nil {
+		for key, value := range d.Policy.Annotations() {
+			inherit(key, value, d.Policy.String())
+		}
+	}
+	for _, key := range NamespaceDefaultAnnotations {
+		if annotations[key] != "" {
+			if _, ok := sources[key]; !ok {
+				sources[key] = SettingSourcePod
+			}
+			continue
+		}
+		if d.Namespace != nil {
+			inherit(key, d.Namespace.Annotations[key], SettingSourceNamespace)
+		}
+	}
+	return annotations, sources
+}
@@ -0,0 +1,145 @@
+package webhook
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+)
+
+func TestWarmupEnabled(t *testing.T) {
+	labeled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "labeled",
+		Labels: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue},
+	}}
+	annotated := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "annotated",
+		Annotations: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue},
+	}}
+	plain := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "plain"}}
+
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		ns          *corev1.Namespace
+		wantEnabled bool
+		wantSource  string
+	}{
+		{name: "pod enables", annotations: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue}, ns: plain, wantEnabled: true, wantSource: SettingSourcePod},
+		{name: "namespace label enables", ns: labeled, wantEnabled: true, wantSource: SettingSourceNamespace},
+		{name: "namespace annotation enables", ns: annotated, wantEnabled: true, wantSource: SettingSourceNamespace},
+		{name: "pod opts out of enabled namespace", annotations: map[string]string{AnnotationWarmupEnabled: "disabled"}, ns: labeled, wantSource: SettingSourcePod},
+		{name: "neither enables", ns: plain},
+		{name: "nil namespace", annotations: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Annotations: tt.annotations}}
+			enabled, source := Defaults{Namespace: tt.ns}.WarmupEnabled(pod)
+			if enabled != tt.wantEnabled || source != tt.wantSource {
+				t.Errorf("WarmupEnabled() = (%v, %q), want (%v, %q)", enabled, source, tt.wantEnabled, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestEffectiveAnnotations(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-a",
+		Annotations: map[string]string{
+			AnnotationWarmupEndpoint: "/warm",
+			AnnotationWarmupRequests: "10",
+			AnnotationWarmupPort:     "9090",
+		},
+	}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name: "test-pod",
+		Annotations: map[string]string{
+			AnnotationWarmupRequests: "20",
+			AnnotationWarmupTimeout:  "",
+		},
+	}}
+
+	annotations, sources := Defaults{Namespace: ns}.EffectiveAnnotations(pod)
+
+	want := map[string]string{
+		AnnotationWarmupEndpoint: "/warm",
+		AnnotationWarmupRequests: "20",
+		AnnotationWarmupTimeout:  "",
+	}
+	if len(annotations) != len(want) {
+		t.Errorf("annotations = %v, want %v", annotations, want)
+	}
+	for k, v := range want {
+		if annotations[k] != v {
+			t.Errorf("annotations[%s] = %q, want %q", k, annotations[k], v)
+		}
+	}
+	wantSources := map[string]string{
+		AnnotationWarmupEndpoint: SettingSourceNamespace,
+		AnnotationWarmupRequests: SettingSourcePod,
+	}
+	if len(sources) != len(wantSources) {
+		t.Errorf("sources = %v, want %v", sources, wantSources)
+	}
+	for k, v := range wantSources {
+		if sources[k] != v {
+			t.Errorf("sources[%s] = %q, want %q", k, sources[k], v)
+		}
+	}
+	if _, ok := pod.Annotations[AnnotationWarmupEndpoint]; ok {
+		t.Error("EffectiveAnnotations() modified the pod")
+	}
+}
+
+func TestDefaults_Policy(t *testing.T) {
+	policy := &MatchedPolicy{
+		Kind:      KindWarmupPolicy,
+		Namespace: "team-a",
+		Name:      "web",
+		Spec: v1alpha1.WarmupPolicySpec{
+			Port:     8080,
+			Settings: &v1alpha1.WarmupPolicySettings{Endpoint: "/policy", Requests: 50},
+		},
+	}
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: "team-a",
+		Annotations: map[string]string{
+			AnnotationWarmupEndpoint: "/namespace",
+			AnnotationWarmupTimeout:  "45s",
+		},
+	}}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:        "test-pod",
+		Annotations: map[string]string{AnnotationWarmupRequests: "20"},
+	}}
+	d := Defaults{Policy: policy, Namespace: ns}
+
+	if enabled, source := d.WarmupEnabled(pod); !enabled || source != "WarmupPolicy team-a/web" {
+		t.Errorf("WarmupEnabled() = (%v, %q), want (true, %q)", enabled, source, "WarmupPolicy team-a/web")
+	}
+
+	annotations, sources := d.EffectiveAnnotations(pod)
+	want := map[string][2]string{
+		AnnotationWarmupPort:     {"8080", "WarmupPolicy team-a/web"},
+		AnnotationWarmupEndpoint: {"/policy", "WarmupPolicy team-a/web"},
+		AnnotationWarmupRequests: {"20", SettingSourcePod},
+		AnnotationWarmupTimeout:  {"45s", SettingSourceNamespace},
+	}
+	if len(sources) != len(want) {
+		t.Errorf("sources = %v, want %d entries", sources, len(want))
+	}
+	for key, w := range want {
+		if annotations[key] != w[0] || sources[key] != w[1] {
+			t.Errorf("%s = (%q, %q), want (%q, %q)", key, annotations[key], sources[key], w[0], w[1])
+		}
+	}
+
+	pod.Annotations[AnnotationWarmupEnabled] = "disabled"
+	if enabled, _ := d.WarmupEnabled(pod); enabled {
+		t.Error("WarmupEnabled() = true, want pod opt-out to win over the policy")
+	}
+}
@@ -3,17 +3,49 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// annotationPrefix is the prefix shared by all kube-booster pod annotations
+const annotationPrefix = "kube-booster.io/"
+
+// knownAnnotations are the pod annotation keys kube-booster reads or writes
+var knownAnnotations = []string{
+	AnnotationWarmupEnabled, AnnotationWarmupEndpoint, AnnotationWarmupRequests,
+	AnnotationWarmupTimeout, AnnotationWarmupPort, AnnotationWarmupProtocol,
+	AnnotationWarmupGRPCMethod, AnnotationWarmupGRPCPayload, AnnotationWarmupStages,
+	AnnotationWarmupRPS, AnnotationWarmupProgress, AnnotationWarmupResult, AnnotationWarmupConfig,
+	AnnotationWarmupConfigParams,
+}
+
+// PodValidator checks the warmup settings of a pod under admission. err is a
+// setting that would make the controller skip the warmup; warnings describe
+// settings that are accepted but have no effect.
+type PodValidator func(pod *corev1.Pod, defaults Defaults) (warnings []string, err error)
+
 // PodMutator handles pod mutation for injecting readiness gates
 type PodMutator struct {
-	Client  client.Client
+	Client client.Client
+
+	// Validate checks the warmup settings of pods that enable warmup (optional).
+	// It is supplied by the caller because the checks share their parsers with the
+	// controller (see warmup.ValidatePod).
+	Validate PodValidator
+
+	// DenyInvalid rejects pods whose warmup settings fail Validate instead of
+	// admitting them with a warning (the controller would skip their warmup).
+	DenyInvalid bool
+
 	decoder admission.Decoder
 }
 
@@ -34,15 +66,33 @@ func (pm *PodMutator) Handle(ctx context.Context, req admission.Request) admissi
 		return admission.Errored(http.StatusBadRequest, err)
 	}
 
-	// Check if warmup is enabled via annotation
-	if pod.Annotations[AnnotationWarmupEnabled] != WarmupEnabledValue {
-		return admission.Allowed("warmup not enabled")
+	warnings := unknownAnnotationWarnings(pod)
+
+	// Check if warmup is enabled via pod annotation, falling back to policies and the namespace
+	defaults := pm.defaults(ctx, req.Namespace, pod)
+	if enabled, _ := defaults.WarmupEnabled(pod); !enabled {
+		if _, ok := pod.Annotations[AnnotationWarmupEnabled]; !ok && hasWarmupSettings(pod) {
+			warnings = append(warnings, fmt.Sprintf("warmup annotations are ignored because warmup is not enabled (set %s: %s)",
+				AnnotationWarmupEnabled, WarmupEnabledValue))
+		}
+		return admission.Allowed("warmup not enabled").WithWarnings(warnings...)
+	}
+
+	if pm.Validate != nil {
+		w, err := pm.Validate(pod, defaults)
+		warnings = append(warnings, w...)
+		if err != nil {
+			if pm.DenyInvalid {
+				return admission.Denied(fmt.Sprintf("invalid warmup configuration: %v", err)).WithWarnings(warnings...)
+			}
+			warnings = append(warnings, fmt.Sprintf("warmup will be skipped: %v", err))
+		}
 	}
 
 	// Check if readiness gate already exists (idempotency)
 	for _, gate := range pod.Spec.ReadinessGates {
 		if gate.ConditionType == corev1.PodConditionType(ReadinessGateName) {
-			return admission.Allowed("readiness gate already present")
+			return admission.Allowed("readiness gate already present").WithWarnings(warnings...)
 		}
 	}
 
@@ -61,7 +111,71 @@ func (pm *PodMutator) Handle(ctx context.Context, req admission.Request) admissi
 	}
 
 	// Return patch response
-	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod)
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledPod).WithWarnings(warnings...)
+}
+
+// unknownAnnotationWarnings warns about kube-booster.io/ annotations that kube-booster
+// does not read, which are most likely misspelled.
+func unknownAnnotationWarnings(pod *corev1.Pod) []string {
+	var unknown []string
+	for key := range pod.Annotations {
+		if !strings.HasPrefix(key, annotationPrefix) || slices.Contains(knownAnnotations, key) ||
+			strings.HasPrefix(key, AnnotationWarmupParamPrefix) {
+			continue
+		}
+		unknown = append(unknown, key)
+	}
+	sort.Strings(unknown)
+
+	warnings := make([]string, 0, len(unknown))
+	for _, key := range unknown {
+		warnings = append(warnings, fmt.Sprintf("unknown annotation %s is ignored", key))
+	}
+	return warnings
+}
+
+// hasWarmupSettings reports whether the pod sets a warmup annotation other than
+// AnnotationWarmupEnabled.
+func hasWarmupSettings(pod *corev1.Pod) bool {
+	for key := range pod.Annotations {
+		if key != AnnotationWarmupEnabled && key != AnnotationWarmupProgress && key != AnnotationWarmupResult &&
+			slices.Contains(knownAnnotations, key) {
+			return true
+		}
+		if strings.HasPrefix(key, AnnotationWarmupParamPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaults returns the policy and namespace the pod under admission inherits from,
+// when a client is configured. They are looked up even when the pod enables warmup
+// itself, since the pod still inherits the settings it does not set, as in the
+// controller. Lookup failures are logged and leave the affected source out rather
+// than blocking the pod's creation.
+func (pm *PodMutator) defaults(ctx context.Context, namespace string, pod *corev1.Pod) Defaults {
+	if pm.Client == nil {
+		return Defaults{}
+	}
+	if namespace == "" {
+		namespace = pod.Namespace
+	}
+	logger := log.FromContext(ctx).WithValues("namespace", namespace)
+
+	var d Defaults
+	ns := &corev1.Namespace{}
+	if err := pm.Client.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		logger.Error(err, "failed to get namespace, ignoring namespace warmup settings")
+	} else {
+		d.Namespace = ns
+	}
+	policy, err := ResolvePolicy(ctx, pm.Client, pod, namespace, d.Namespace)
+	if err != nil {
+		logger.Error(err, "failed to resolve warmup policy, ignoring warmup policies")
+	}
+	d.Policy = policy
+	return d
 }
 
 // InjectDecoder injects the decoder
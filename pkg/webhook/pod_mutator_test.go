@@ -3,12 +3,17 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"slices"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
 )
 
 func TestPodMutator_Handle(t *testing.T) {
@@ -146,6 +151,184 @@ func TestPodMutator_Handle(t *testing.T) {
 	}
 }
 
+func TestPodMutator_Warnings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	// validate stands in for warmup.ValidatePod: it fails for a non-numeric request count
+	validate := func(pod *corev1.Pod, _ Defaults) ([]string, error) {
+		if pod.Annotations[AnnotationWarmupRequests] == "lots" {
+			return []string{"validator warning"}, errors.New("invalid warmup-requests value")
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		name         string
+		annotations  map[string]string
+		denyInvalid  bool
+		wantAllowed  bool
+		wantPatches  bool
+		wantWarnings []string
+	}{
+		{
+			name:        "valid settings",
+			annotations: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue, AnnotationWarmupRequests: "5"},
+			wantAllowed: true,
+			wantPatches: true,
+		},
+		{
+			name:        "invalid settings warn",
+			annotations: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue, AnnotationWarmupRequests: "lots"},
+			wantAllowed: true,
+			wantPatches: true,
+			wantWarnings: []string{
+				"validator warning",
+				"warmup will be skipped: invalid warmup-requests value",
+			},
+		},
+		{
+			name:        "invalid settings denied",
+			annotations: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue, AnnotationWarmupRequests: "lots"},
+			denyInvalid: true,
+			wantAllowed: false,
+			wantWarnings: []string{
+				"validator warning",
+			},
+		},
+		{
+			name: "unknown annotation",
+			annotations: map[string]string{
+				AnnotationWarmupEnabled:          WarmupEnabledValue,
+				"kube-booster.io/warmup-request": "5",
+				"kube-booster.io/param.tenant":   "acme",
+				"example.com/other":              "x",
+			},
+			wantAllowed:  true,
+			wantPatches:  true,
+			wantWarnings: []string{"unknown annotation kube-booster.io/warmup-request is ignored"},
+		},
+		{
+			name:         "settings without opt-in",
+			annotations:  map[string]string{AnnotationWarmupRequests: "lots"},
+			wantAllowed:  true,
+			wantWarnings: []string{"warmup annotations are ignored because warmup is not enabled (set kube-booster.io/warmup: enabled)"},
+		},
+		{
+			name:        "explicitly disabled",
+			annotations: map[string]string{AnnotationWarmupEnabled: "disabled", AnnotationWarmupRequests: "lots"},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutator := &PodMutator{
+				Validate:    validate,
+				DenyInvalid: tt.denyInvalid,
+				decoder:     admission.NewDecoder(scheme),
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default", Annotations: tt.annotations},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "test", Image: "nginx"}}},
+			}
+			podBytes, err := json.Marshal(pod)
+			if err != nil {
+				t.Fatalf("failed to marshal pod: %v", err)
+			}
+			req := admission.Request{}
+			req.Object = runtime.RawExtension{Raw: podBytes}
+
+			resp := mutator.Handle(context.Background(), req)
+
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("Handle() allowed = %v, want %v", resp.Allowed, tt.wantAllowed)
+			}
+			if gotPatches := len(resp.Patches) > 0; gotPatches != tt.wantPatches {
+				t.Errorf("Handle() gotPatches = %v, wantPatches = %v", gotPatches, tt.wantPatches)
+			}
+			if !slices.Equal(resp.Warnings, tt.wantWarnings) {
+				t.Errorf("Handle() warnings = %q, want %q", resp.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestPodMutator_InheritedOptIn(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	enabledNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "team-a",
+		Labels: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue},
+	}}
+	plainNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}}
+	policy := &v1alpha1.WarmupPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-b"},
+		Spec: v1alpha1.WarmupPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}},
+			Settings:    &v1alpha1.WarmupPolicySettings{Endpoint: "/warm"},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(enabledNS, plainNS, policy).Build()
+
+	tests := []struct {
+		name        string
+		namespace   string
+		labels      map[string]string
+		annotations map[string]string
+		wantPatches bool
+
+		// wantEndpoint is the endpoint the validator sees
+		wantEndpoint string
+	}{
+		{name: "namespace label enables warmup", namespace: "team-a", wantPatches: true},
+		{name: "pod annotation opts out", namespace: "team-a", annotations: map[string]string{AnnotationWarmupEnabled: "disabled"}},
+		{name: "namespace without opt-in", namespace: "team-b"},
+		{name: "policy selects pod", namespace: "team-b", labels: map[string]string{"app": "web"}, wantPatches: true, wantEndpoint: "/warm"},
+		{name: "pod annotation inherits policy settings", namespace: "team-b", labels: map[string]string{"app": "web"},
+			annotations: map[string]string{AnnotationWarmupEnabled: WarmupEnabledValue}, wantPatches: true, wantEndpoint: "/warm"},
+		{name: "pod annotation opts out of policy", namespace: "team-b", labels: map[string]string{"app": "web"},
+			annotations: map[string]string{AnnotationWarmupEnabled: "disabled"}},
+		{name: "missing namespace fails open", namespace: "gone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotEndpoint string
+			mutator := NewPodMutator(c, scheme)
+			mutator.Validate = func(pod *corev1.Pod, defaults Defaults) ([]string, error) {
+				annotations, _ := defaults.EffectiveAnnotations(pod)
+				gotEndpoint = annotations[AnnotationWarmupEndpoint]
+				return nil, nil
+			}
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Labels: tt.labels, Annotations: tt.annotations},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "test", Image: "nginx"}}},
+			}
+			podBytes, err := json.Marshal(pod)
+			if err != nil {
+				t.Fatalf("failed to marshal pod: %v", err)
+			}
+			req := admission.Request{}
+			req.Namespace = tt.namespace
+			req.Object = runtime.RawExtension{Raw: podBytes}
+
+			resp := mutator.Handle(context.Background(), req)
+			if !resp.Allowed {
+				t.Fatalf("Handle() allowed = false, want true")
+			}
+			if gotPatches := len(resp.Patches) > 0; gotPatches != tt.wantPatches {
+				t.Errorf("Handle() gotPatches = %v, wantPatches = %v", gotPatches, tt.wantPatches)
+			}
+			if gotEndpoint != tt.wantEndpoint {
+				t.Errorf("validated endpoint = %q, want %q", gotEndpoint, tt.wantEndpoint)
+			}
+		})
+	}
+}
+
 func TestPodMutator_InjectDecoder(t *testing.T) {
 	scheme := runtime.NewScheme()
 	_ = corev1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+)
+
+const (
+	// KindWarmupPolicy is the kind of namespaced warmup policies
+	KindWarmupPolicy = "WarmupPolicy"
+
+	// KindClusterWarmupPolicy is the kind of cluster-scoped warmup policies
+	KindClusterWarmupPolicy = "ClusterWarmupPolicy"
+)
+
+// MatchedPolicy is the WarmupPolicy or ClusterWarmupPolicy that applies to a pod.
+type MatchedPolicy struct {
+	Kind      string
+	Namespace string // empty for ClusterWarmupPolicy
+	Name      string
+	Spec      v1alpha1.WarmupPolicySpec
+}
+
+// String returns the kind and name of the policy, e.g. "WarmupPolicy team-a/web".
+func (p *MatchedPolicy) String() string {
+	if p.Namespace == "" {
+		return fmt.Sprintf("%s %s", p.Kind, p.Name)
+	}
+	return fmt.Sprintf("%s %s/%s", p.Kind, p.Namespace, p.Name)
+}
+
+// Annotations returns the policy's settings as the pod annotations they stand in for.
+func (p *MatchedPolicy) Annotations() map[string]string {
+	annotations := map[string]string{}
+	set := func(key, value string) {
+		if value != "" {
+			annotations[key] = value
+		}
+	}
+	if p.Spec.Port > 0 {
+		set(AnnotationWarmupPort, strconv.Itoa(p.Spec.Port))
+	}
+	if s := p.Spec.Settings; s != nil {
+		set(AnnotationWarmupEndpoint, s.Endpoint)
+		if s.Requests > 0 {
+			set(AnnotationWarmupRequests, strconv.Itoa(s.Requests))
+		}
+		set(AnnotationWarmupTimeout, s.Timeout)
+		set(AnnotationWarmupProtocol, s.Protocol)
+		set(AnnotationWarmupGRPCMethod, s.GRPCMethod)
+		set(AnnotationWarmupGRPCPayload, s.GRPCPayload)
+	}
+	if ref := p.Spec.WarmupConfigRef; ref != nil && ref.Name != "" {
+		if ref.Kind == KindClusterWarmupConfig {
+			set(AnnotationWarmupConfig, KindClusterWarmupConfig+"/"+ref.Name)
+		} else {
+			set(AnnotationWarmupConfig, ref.Name)
+		}
+	}
+	return annotations
+}
+
+// ResolvePolicy returns the highest-priority WarmupPolicy or ClusterWarmupPolicy
+// that selects pod, or nil if none does. namespace is the pod's namespace; ns is
+// its Namespace object, used for ClusterWarmupPolicy namespace selectors, and may
+// be nil (only policies without a namespace selector then match). A cluster
+// without the policy CRDs installed has no policies.
+func ResolvePolicy(ctx context.Context, c client.Reader, pod *corev1.Pod, namespace string, ns *corev1.Namespace) (*MatchedPolicy, error) {
+	var candidates []*MatchedPolicy
+
+	policies := &v1alpha1.WarmupPolicyList{}
+	if err := c.List(ctx, policies, client.InNamespace(namespace)); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("listing WarmupPolicies: %w", err)
+	}
+	for i := range policies.Items {
+		p := &policies.Items[i]
+		candidates = append(candidates, &MatchedPolicy{Kind: KindWarmupPolicy, Namespace: p.Namespace, Name: p.Name, Spec: p.Spec})
+	}
+
+	clusterPolicies := &v1alpha1.ClusterWarmupPolicyList{}
+	if err := c.List(ctx, clusterPolicies); err != nil && !meta.IsNoMatchError(err) {
+		return nil, fmt.Errorf("listing ClusterWarmupPolicies: %w", err)
+	}
+	for i := range clusterPolicies.Items {
+		p := &clusterPolicies.Items[i]
+		candidates = append(candidates, &MatchedPolicy{Kind: KindClusterWarmupPolicy, Name: p.Name, Spec: p.Spec})
+	}
+
+	return selectPolicy(pod, ns, candidates), nil
+}
+
+// selectPolicy returns the candidate that selects pod and wins by priority, then
+// kind (WarmupPolicy first), then name.
+func selectPolicy(pod *corev1.Pod, ns *corev1.Namespace, candidates []*MatchedPolicy) *MatchedPolicy {
+	var best *MatchedPolicy
+	for _, p := range candidates {
+		if !p.selects(pod, ns) {
+			continue
+		}
+		if best == nil || p.before(best) {
+			best = p
+		}
+	}
+	return best
+}
+
+// selects reports whether the policy applies to pod. Invalid selectors select nothing.
+func (p *MatchedPolicy) selects(pod *corev1.Pod, ns *corev1.Namespace) bool {
+	if p.Kind == KindClusterWarmupPolicy && p.Spec.NamespaceSelector != nil {
+		if ns == nil || !selectorMatches(p.Spec.NamespaceSelector, ns.Labels) {
+			return false
+		}
+	}
+	return selectorMatches(&p.Spec.PodSelector, pod.Labels)
+}
+
+// before reports whether p takes precedence over other.
+func (p *MatchedPolicy) before(other *MatchedPolicy) bool {
+	if p.Spec.Priority != other.Spec.Priority {
+		return p.Spec.Priority > other.Spec.Priority
+	}
+	if p.Kind != other.Kind {
+		return p.Kind == KindWarmupPolicy
+	}
+	return p.Name < other.Name
+}
+
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(labels.Set(set))
+}
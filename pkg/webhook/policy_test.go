@@ -0,0 +1,172 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+)
+
+func TestSelectPolicy(t *testing.T) {
+	webSelector := metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+	prodSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}
+
+	namespaced := func(name string, priority int32) *MatchedPolicy {
+		return &MatchedPolicy{Kind: KindWarmupPolicy, Namespace: "team-a", Name: name,
+			Spec: v1alpha1.WarmupPolicySpec{PodSelector: webSelector, Priority: priority}}
+	}
+	cluster := func(name string, priority int32, nsSelector *metav1.LabelSelector) *MatchedPolicy {
+		return &MatchedPolicy{Kind: KindClusterWarmupPolicy, Name: name,
+			Spec: v1alpha1.WarmupPolicySpec{Priority: priority, NamespaceSelector: nsSelector}}
+	}
+
+	prodNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"env": "prod"}}}
+	devNS := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"env": "dev"}}}
+
+	tests := []struct {
+		name       string
+		podLabels  map[string]string
+		ns         *corev1.Namespace
+		candidates []*MatchedPolicy
+		want       string
+	}{
+		{
+			name:       "no candidates",
+			podLabels:  map[string]string{"app": "web"},
+			candidates: nil,
+		},
+		{
+			name:       "pod selector does not match",
+			podLabels:  map[string]string{"app": "api"},
+			candidates: []*MatchedPolicy{namespaced("web", 0)},
+		},
+		{
+			name:       "highest priority wins",
+			podLabels:  map[string]string{"app": "web"},
+			ns:         prodNS,
+			candidates: []*MatchedPolicy{namespaced("low", 1), cluster("high", 5, nil), namespaced("mid", 3)},
+			want:       "ClusterWarmupPolicy high",
+		},
+		{
+			name:       "WarmupPolicy wins a priority tie",
+			podLabels:  map[string]string{"app": "web"},
+			candidates: []*MatchedPolicy{cluster("all", 0, nil), namespaced("web", 0)},
+			want:       "WarmupPolicy team-a/web",
+		},
+		{
+			name:       "name breaks remaining ties",
+			podLabels:  map[string]string{"app": "web"},
+			candidates: []*MatchedPolicy{namespaced("b", 0), namespaced("a", 0)},
+			want:       "WarmupPolicy team-a/a",
+		},
+		{
+			name:       "namespace selector matches",
+			podLabels:  map[string]string{"app": "web"},
+			ns:         prodNS,
+			candidates: []*MatchedPolicy{cluster("prod", 0, prodSelector)},
+			want:       "ClusterWarmupPolicy prod",
+		},
+		{
+			name:       "namespace selector does not match",
+			podLabels:  map[string]string{"app": "web"},
+			ns:         devNS,
+			candidates: []*MatchedPolicy{cluster("prod", 0, prodSelector)},
+		},
+		{
+			name:       "namespace selector without namespace",
+			podLabels:  map[string]string{"app": "web"},
+			candidates: []*MatchedPolicy{cluster("prod", 0, prodSelector)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "team-a", Labels: tt.podLabels}}
+			got := selectPolicy(pod, tt.ns, tt.candidates)
+			var gotName string
+			if got != nil {
+				gotName = got.String()
+			}
+			if gotName != tt.want {
+				t.Errorf("selectPolicy() = %q, want %q", gotName, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)   //nolint:errcheck // scheme registration never fails
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		// Same name in another namespace must not apply
+		&v1alpha1.WarmupPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-b"},
+			Spec:       v1alpha1.WarmupPolicySpec{Priority: 100},
+		},
+		&v1alpha1.WarmupPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"},
+			Spec: v1alpha1.WarmupPolicySpec{
+				WarmupConfigRef: &v1alpha1.WarmupConfigReference{Name: "checkout"},
+			},
+		},
+	).Build()
+
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "team-a"}}
+	policy, err := ResolvePolicy(context.Background(), c, pod, "team-a", nil)
+	if err != nil {
+		t.Fatalf("ResolvePolicy() error = %v", err)
+	}
+	if policy == nil || policy.String() != "WarmupPolicy team-a/web" {
+		t.Fatalf("ResolvePolicy() = %v, want WarmupPolicy team-a/web", policy)
+	}
+	if got := policy.Annotations()[AnnotationWarmupConfig]; got != "checkout" {
+		t.Errorf("Annotations()[%s] = %q, want %q", AnnotationWarmupConfig, got, "checkout")
+	}
+}
+
+func TestMatchedPolicy_Annotations(t *testing.T) {
+	p := &MatchedPolicy{Spec: v1alpha1.WarmupPolicySpec{
+		Port: 8080,
+		Settings: &v1alpha1.WarmupPolicySettings{
+			Endpoint:   "/warm",
+			Requests:   20,
+			Timeout:    "1m",
+			Protocol:   "grpc",
+			GRPCMethod: "pkg.Svc/Call",
+		},
+	}}
+
+	want := map[string]string{
+		AnnotationWarmupPort:       "8080",
+		AnnotationWarmupEndpoint:   "/warm",
+		AnnotationWarmupRequests:   "20",
+		AnnotationWarmupTimeout:    "1m",
+		AnnotationWarmupProtocol:   "grpc",
+		AnnotationWarmupGRPCMethod: "pkg.Svc/Call",
+	}
+	got := p.Annotations()
+	if len(got) != len(want) {
+		t.Errorf("Annotations() = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Annotations()[%s] = %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestMatchedPolicy_Annotations_ClusterWarmupConfig(t *testing.T) {
+	p := &MatchedPolicy{Spec: v1alpha1.WarmupPolicySpec{
+		WarmupConfigRef: &v1alpha1.WarmupConfigReference{Kind: KindClusterWarmupConfig, Name: "jvm-spring"},
+	}}
+	if got := p.Annotations()[AnnotationWarmupConfig]; got != "ClusterWarmupConfig/jvm-spring" {
+		t.Errorf("Annotations()[%s] = %q, want %q", AnnotationWarmupConfig, got, "ClusterWarmupConfig/jvm-spring")
+	}
+}
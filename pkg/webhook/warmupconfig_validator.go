@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+)
+
+// SpecValidator checks a WarmupConfigSpec found at path. It returns the errors that
+// reject the object and warnings that are shown to the client.
+type SpecValidator func(spec *v1alpha1.WarmupConfigSpec, path *field.Path) (field.ErrorList, []string)
+
+// WarmupConfigValidator rejects WarmupConfig and ClusterWarmupConfig objects whose
+// scenario would fail or silently fall back to defaults at runtime.
+type WarmupConfigValidator struct {
+	// Validate performs the checks. It is supplied by the caller because the checks
+	// share their parsers with the scenario executor (see warmup.ValidateWarmupConfigSpec).
+	Validate SpecValidator
+	decoder  admission.Decoder
+}
+
+// NewWarmupConfigValidator creates a new WarmupConfigValidator with the given scheme
+// and checks
+func NewWarmupConfigValidator(scheme *runtime.Scheme, validate SpecValidator) *WarmupConfigValidator {
+	return &WarmupConfigValidator{
+		Validate: validate,
+		decoder:  admission.NewDecoder(scheme),
+	}
+}
+
+// Handle processes the admission request
+func (v *WarmupConfigValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete {
+		return admission.Allowed("")
+	}
+
+	var spec *v1alpha1.WarmupConfigSpec
+	switch req.Kind.Kind {
+	case KindWarmupConfig:
+		cfg := &v1alpha1.WarmupConfig{}
+		if err := v.decoder.DecodeRaw(req.Object, cfg); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		spec = &cfg.Spec
+	case KindClusterWarmupConfig:
+		cfg := &v1alpha1.ClusterWarmupConfig{}
+		if err := v.decoder.DecodeRaw(req.Object, cfg); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		spec = &cfg.Spec.WarmupConfigSpec
+	default:
+		return admission.Allowed("not a warmup config")
+	}
+
+	errs, warnings := v.Validate(spec, field.NewPath("spec"))
+	if len(errs) > 0 {
+		gk := schema.GroupKind{Group: req.Kind.Group, Kind: req.Kind.Kind}
+		return admission.Errored(http.StatusUnprocessableEntity, apierrors.NewInvalid(gk, req.Name, errs)).
+			WithWarnings(warnings...)
+	}
+	return admission.Allowed("").WithWarnings(warnings...)
+}
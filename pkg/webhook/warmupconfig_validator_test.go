@@ -0,0 +1,115 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	v1alpha1 "github.com/hhiroshell/kube-booster/pkg/api/v1alpha1"
+)
+
+func TestWarmupConfigValidator_Handle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = v1alpha1.AddToScheme(scheme) //nolint:errcheck // scheme registration never fails
+
+	// The stub rejects steps named "bad" and warns about steps named "odd".
+	validate := func(spec *v1alpha1.WarmupConfigSpec, path *field.Path) (field.ErrorList, []string) {
+		var errs field.ErrorList
+		var warnings []string
+		for i, step := range spec.Steps {
+			switch step.Name {
+			case "bad":
+				errs = append(errs, field.Invalid(path.Child("steps").Index(i).Child("name"), step.Name, "is bad"))
+			case "odd":
+				warnings = append(warnings, "odd step")
+			}
+		}
+		return errs, warnings
+	}
+	validator := NewWarmupConfigValidator(scheme, validate)
+
+	spec := func(name string) v1alpha1.WarmupConfigSpec {
+		return v1alpha1.WarmupConfigSpec{Steps: []v1alpha1.WarmupStep{{Name: name}}}
+	}
+	tests := []struct {
+		name         string
+		kind         string
+		operation    admissionv1.Operation
+		obj          runtime.Object
+		wantAllowed  bool
+		wantMessage  string
+		wantWarnings int
+	}{
+		{
+			name:        "valid WarmupConfig",
+			kind:        KindWarmupConfig,
+			obj:         &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "cfg"}, Spec: spec("ok")},
+			wantAllowed: true,
+		},
+		{
+			name:         "warnings are returned",
+			kind:         KindWarmupConfig,
+			obj:          &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "cfg"}, Spec: spec("odd")},
+			wantAllowed:  true,
+			wantWarnings: 1,
+		},
+		{
+			name:        "invalid WarmupConfig",
+			kind:        KindWarmupConfig,
+			obj:         &v1alpha1.WarmupConfig{ObjectMeta: metav1.ObjectMeta{Name: "cfg"}, Spec: spec("bad")},
+			wantMessage: `WarmupConfig.kube-booster.io "cfg" is invalid: spec.steps[0].name: Invalid value: "bad": is bad`,
+		},
+		{
+			name: "invalid ClusterWarmupConfig",
+			kind: KindClusterWarmupConfig,
+			obj: &v1alpha1.ClusterWarmupConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "cfg"},
+				Spec:       v1alpha1.ClusterWarmupConfigSpec{WarmupConfigSpec: spec("bad")},
+			},
+			wantMessage: "spec.steps[0].name",
+		},
+		{
+			name:        "delete is allowed",
+			kind:        KindWarmupConfig,
+			operation:   admissionv1.Delete,
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := admission.Request{}
+			req.Kind = metav1.GroupVersionKind{Group: v1alpha1.SchemeGroupVersion.Group, Version: v1alpha1.SchemeGroupVersion.Version, Kind: tt.kind}
+			req.Name = "cfg"
+			req.Operation = admissionv1.Create
+			if tt.operation != "" {
+				req.Operation = tt.operation
+			}
+			if tt.obj != nil {
+				raw, err := json.Marshal(tt.obj)
+				if err != nil {
+					t.Fatalf("failed to marshal object: %v", err)
+				}
+				req.Object = runtime.RawExtension{Raw: raw}
+			}
+
+			resp := validator.Handle(context.Background(), req)
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("Handle() allowed = %v, want %v (result: %v)", resp.Allowed, tt.wantAllowed, resp.Result)
+			}
+			if tt.wantMessage != "" && (resp.Result == nil || !strings.Contains(resp.Result.Message, tt.wantMessage)) {
+				t.Errorf("Handle() result = %v, want message containing %q", resp.Result, tt.wantMessage)
+			}
+			if len(resp.Warnings) != tt.wantWarnings {
+				t.Errorf("Handle() warnings = %v, want %d", resp.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}